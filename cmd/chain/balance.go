@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/p2p"
+	"golang-chain/pkg/p2p/pb"
+	"golang-chain/pkg/state"
+	"golang-chain/pkg/wallet"
+)
+
+// balanceInfo is the result of the balance command
+type balanceInfo struct {
+	Account string `json:"account"`
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+	Nonce   uint64 `json:"nonce"`
+	// Verified is set with --prove, along with the block the proof was checked against
+	Verified bool                    `json:"verified"`
+	Block    *blockchain.BlockHeader `json:"block,omitempty"`
+	// Trusted tells whether that block came from --header or --trusted-node, rather
+	// than from the node that answered
+	Trusted bool `json:"trusted"`
+}
+
+// balance shows the balance of an account, and with --prove checks it against a state root
+func balance(args []string) error {
+	fs := newFlagSet("balance")
+	name := fs.String("name", "", "Wallet or contact name")
+	addressFlag := fs.String("address", "", "Account address, instead of a name")
+	prove := fs.Bool("prove", false, "Ask the node for a state proof and verify it")
+	headerPath := fs.String("header", "", "Trusted block header (JSON) to verify the proof against")
+	trustedNode := fs.String("trusted-node", "", "Node to fetch the trusted block header from (host:port)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" && *addressFlag == "" {
+		return usageError("usage: chain balance --name Alice | --address gc1...")
+	}
+	address, label, err := resolveAccount(*name, *addressFlag)
+	if err != nil {
+		return err
+	}
+
+	c, err := dialNode()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	resp, err := c.client.GetBalance(context.Background(), &pb.BalanceRequest{Address: address, Prove: *prove})
+	if err != nil {
+		return rpcError("get balance", err)
+	}
+
+	info := balanceInfo{Account: firstOf(*name, wallet.ResolveAddressName(address)), Address: wallet.DisplayAddress(address), Balance: resp.Balance, Nonce: resp.Nonce}
+	if *prove {
+		acc, header, err := verifyBalance(address, resp, *headerPath, *trustedNode)
+		if err != nil {
+			return err
+		}
+		info.Balance = acc.Balance.Text('f', 2)
+		info.Nonce = acc.Nonce
+		info.Verified = true
+		info.Block = header
+		info.Trusted = *headerPath != "" || *trustedNode != ""
+	}
+	return emit(info, func() {
+		fmt.Printf("💰 Balance of %s: %s coins\n", label, info.Balance)
+		switch {
+		case info.Trusted:
+			fmt.Printf("✅ Verified against the state root of block #%d (%s)\n", info.Block.Height, info.Block.CurrentBlockHash)
+		case info.Verified:
+			fmt.Printf("⚠️  Consistent with the state root of block #%d sent by the same node, give --header or --trusted-node to verify it\n", info.Block.Height)
+		}
+	})
+}
+
+// resolveAccount returns the account address of the named wallet or contact, or of
+// the encoded address, and how to show it
+func resolveAccount(name, encoded string) (address, label string, err error) {
+	if encoded != "" {
+		address, err := wallet.ParseAddress(encoded)
+		if err != nil {
+			return "", "", usageError("%v", err)
+		}
+		return address, wallet.ResolveAddressName(address), nil
+	}
+
+	address, err = wallet.ResolveAccount(name)
+	if err != nil {
+		return "", "", accountError(err)
+	}
+	return address, fmt.Sprintf("%s (%s)", name, wallet.DisplayAddress(address)), nil
+}
+
+// accountError is the error of wallet.ResolveAccount: a name that is neither a
+// wallet, a contact nor an address wasn't found
+func accountError(err error) error {
+	if errors.Is(err, wallet.ErrInvalidAddress) {
+		return failf(exitNotFound, "%w", err)
+	}
+	return err
+}
+
+// verifyBalance checks the proof in resp against a trusted header and returns the proven account.
+// The trusted header comes from a file, from a node the user trusts, or, lacking both,
+// from the answering node itself, which only shows that the answer is consistent.
+func verifyBalance(address string, resp *pb.BalanceResponse, headerPath, trustedNode string) (*state.Account, *blockchain.BlockHeader, error) {
+	if resp.Header == nil || resp.Proof == nil {
+		return nil, nil, failf(exitInvalid, "node didn't return a proof")
+	}
+	header := p2p.PbToHeader(resp.Header)
+
+	var trusted *blockchain.BlockHeader
+	switch {
+	case headerPath != "":
+		data, err := os.ReadFile(headerPath)
+		if err != nil {
+			return nil, nil, fileError("read header", err)
+		}
+		trusted = new(blockchain.BlockHeader)
+		if err := json.Unmarshal(data, trusted); err != nil {
+			return nil, nil, usageError("failed to read header: %v", err)
+		}
+		if trusted.Version >= blockchain.BlockVersion2 && blockchain.HashHeader(trusted) != trusted.CurrentBlockHash {
+			return nil, nil, failf(exitInvalid, "trusted header doesn't match its own hash")
+		}
+	case trustedNode != "":
+		var err error
+		if trusted, err = fetchHeader(trustedNode, header.Height); err != nil {
+			return nil, nil, err
+		}
+	default:
+		trusted = header
+	}
+
+	if trusted.CurrentBlockHash != header.CurrentBlockHash {
+		return nil, nil, failf(exitInvalid, "proof is for block #%d (%s), trusted header is block #%d (%s)",
+			header.Height, header.CurrentBlockHash, trusted.Height, trusted.CurrentBlockHash)
+	}
+
+	acc, err := state.DecodeAccount(resp.Proof.Account)
+	if err != nil {
+		return nil, nil, failf(exitInvalid, "invalid account in proof: %w", err)
+	}
+	proof := &state.Proof{Bitmap: resp.Proof.Bitmap, Siblings: resp.Proof.Siblings}
+	ok, err := state.VerifyProof(trusted.StateRoot, address, acc, proof)
+	if err != nil {
+		return nil, nil, failf(exitInvalid, "invalid proof: %w", err)
+	}
+	if !ok {
+		return nil, nil, failf(exitInvalid, "proof does NOT match state root %s", trusted.StateRoot)
+	}
+	return acc, trusted, nil
+}
+
+// fetchHeader gets the header of the block at height from the given node
+func fetchHeader(node string, height int64) (*blockchain.BlockHeader, error) {
+	c, err := dial(node)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	// Headers stay available on a pruning node, full blocks may not
+	resp, err := c.client.GetHeaderByHeight(context.Background(), &pb.HeightRequest{Height: height})
+	if err != nil {
+		return nil, rpcError(fmt.Sprintf("get header #%d from trusted node", height), err)
+	}
+	return p2p.PbToHeader(resp), nil
+}
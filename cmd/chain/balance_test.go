@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/p2p/pb"
+	"golang-chain/pkg/state"
+)
+
+// balanceResponse returns what a node answers for address in a state of a few accounts,
+// and the block the answer is for
+func balanceResponse(t *testing.T, address string) (*pb.BalanceResponse, *blockchain.Block) {
+	t.Helper()
+	st := state.New(nil)
+	for i, addr := range []string{"alice", "bob", "carol"} {
+		acc := state.NewAccount()
+		acc.Balance.SetFloat64(float64(i + 1))
+		st.Set(addr, acc)
+	}
+	root, err := st.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	block := blockchain.NewBlock(nil, "parent", 7, root)
+
+	acc, _ := st.Get(address)
+	data, err := acc.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	proof, err := st.Prove(address)
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	h := block.Header()
+	return &pb.BalanceResponse{
+		Balance: acc.Balance.Text('f', 8),
+		Header: &pb.BlockHeader{
+			MerkleRoot:       h.MerkleRoot,
+			PrevBlockHash:    h.PrevBlockHash,
+			CurrentBlockHash: h.CurrentBlockHash,
+			Height:           h.Height,
+			StateRoot:        h.StateRoot,
+			Version:          h.Version,
+		},
+		Proof: &pb.StateProof{Account: data, Bitmap: proof.Bitmap, Siblings: proof.Siblings},
+	}, block
+}
+
+// headerFile writes header as a trusted header file and returns its path
+func headerFile(t *testing.T, header *blockchain.BlockHeader) string {
+	t.Helper()
+	data, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "header.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestVerifyBalance(t *testing.T) {
+	resp, block := balanceResponse(t, "bob")
+	absent, _ := balanceResponse(t, "mallory")
+
+	otherBlock := blockchain.NewBlock(nil, "parent", 8, block.StateRoot)
+	// A header claiming another state root under the block's hash
+	forged := block.Header()
+	forged.StateRoot = strings.Repeat("0", 64)
+
+	tampered := func(edit func(r *pb.BalanceResponse)) *pb.BalanceResponse {
+		r, _ := balanceResponse(t, "bob")
+		edit(r)
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		address string
+		resp    *pb.BalanceResponse
+		header  string
+		balance string // empty when verification must fail
+	}{
+		{"inclusion", "bob", resp, "", "2.00000000"},
+		{"inclusion against a header file", "bob", resp, headerFile(t, block.Header()), "2.00000000"},
+		{"absent account", "mallory", absent, "", "0.00000000"},
+		{"proof for another address", "alice", resp, "", ""},
+		{"no proof", "bob", &pb.BalanceResponse{Header: resp.Header}, "", ""},
+		{"inflated balance", "bob", tampered(func(r *pb.BalanceResponse) {
+			r.Proof.Account = []byte(`{"balance":"100.00000000","nonce":0}`)
+		}), "", ""},
+		{"tampered sibling", "bob", tampered(func(r *pb.BalanceResponse) {
+			r.Proof.Siblings[0] = "00" + r.Proof.Siblings[0][2:]
+		}), "", ""},
+		{"tampered bitmap", "bob", tampered(func(r *pb.BalanceResponse) {
+			r.Proof.Bitmap = "ff" + r.Proof.Bitmap[2:]
+		}), "", ""},
+		{"wrong state root", "bob", tampered(func(r *pb.BalanceResponse) {
+			r.Header.StateRoot = strings.Repeat("0", 64)
+		}), "", ""},
+		{"header file of another block", "bob", resp, headerFile(t, otherBlock.Header()), ""},
+		{"header file that doesn't match its hash", "bob", resp, headerFile(t, forged), ""},
+	}
+	for _, tc := range tests {
+		acc, _, err := verifyBalance(tc.address, tc.resp, tc.header, "")
+		if tc.balance == "" {
+			var cerr *cliError
+			if !errors.As(err, &cerr) || cerr.code != exitInvalid {
+				t.Errorf("%s: want an invalid proof error, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		} else if got := acc.Balance.Text('f', 8); got != tc.balance {
+			t.Errorf("%s: balance %s, want %s", tc.name, got, tc.balance)
+		}
+	}
+}
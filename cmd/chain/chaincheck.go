@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/consensus"
+	"golang-chain/pkg/state"
+	"golang-chain/pkg/storage"
+	"golang-chain/pkg/wallet"
+)
+
+// maxDiffLines caps the account diff so a badly broken state doesn't flood the terminal
+const maxDiffLines = 20
+
+// chaincheckInfo is the result of the chaincheck command
+type chaincheckInfo struct {
+	DB         string `json:"db"`
+	FromHeight int64  `json:"fromHeight"`
+	ToHeight   int64  `json:"toHeight"`
+	Blocks     int64  `json:"blocks"`
+	// Accounts is the number of accounts checked, only set when the full chain is
+	// there to recompute the balances from
+	Accounts *int `json:"accounts,omitempty"`
+}
+
+// chaincheck verifies every block in the database of a stopped node and, when the
+// chain is complete, recomputes the balances and compares them with the stored ones
+func chaincheck(args []string) error {
+	fs := newFlagSet("chaincheck")
+	dbPath := fs.String("db", "", "Data directory of the node (e.g. data/node1)")
+	engine := fs.String("engine", storage.EngineLevelDB, "Storage engine: leveldb or bbolt")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *dbPath == "" {
+		return usageError("usage: chain chaincheck --db data/node1 [--engine leveldb]")
+	}
+
+	db, err := storage.OpenDBReadOnly(*engine, *dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s read-only (is the node still running?): %w", *dbPath, err)
+	}
+	defer db.Close()
+
+	if v, err := db.StoredSchemaVersion(); err != nil || v != storage.SchemaVersion {
+		return fmt.Errorf("schema version is %d, this tool reads version %d (start the node once to migrate): %v", v, storage.SchemaVersion, err)
+	}
+
+	rng, err := db.GetRetainedRange()
+	if err != nil {
+		return fmt.Errorf("failed to read the chain head: %w", err)
+	}
+	w := out()
+	fmt.Fprintf(w, "🔍 Checking %s: heights %d..%d\n", *dbPath, rng.BodiesFrom, rng.Latest)
+
+	// Balances can only be recomputed when every block is still there
+	fullChain := rng.BodiesFrom == 0
+	if !fullChain {
+		fmt.Fprintf(w, "⚠️  Full blocks are only kept from height %d (pruned or fast-synced), balances can't be recomputed\n", rng.BodiesFrom)
+	}
+
+	var prev *blockchain.Block
+	if !fullChain && rng.BodiesFrom > rng.HeadersFrom {
+		header, err := db.GetHeaderByHeight(rng.BodiesFrom - 1)
+		if err != nil {
+			return failf(exitInvalid, "header at height %d is missing: %w", rng.BodiesFrom-1, err)
+		}
+		prev = blockchain.AssembleBlock(header, nil)
+	}
+
+	st := state.New(nil)
+	for h := rng.BodiesFrom; h <= rng.Latest; h++ {
+		block, err := db.GetBlockByHeight(h)
+		if err != nil {
+			return failf(exitInvalid, "first inconsistency at height %d: block can't be read: %w", h, err)
+		}
+		if block.Height != h {
+			return failf(exitInvalid, "first inconsistency at height %d: height index points to block %d", h, block.Height)
+		}
+		if h == 0 && block.CurrentBlockHash != blockchain.CreateGenesisBlock().CurrentBlockHash {
+			return failf(exitInvalid, "first inconsistency at height 0: unexpected genesis block %s", block.CurrentBlockHash)
+		}
+
+		// Blocks from before state roots can't be checked against one
+		var preState *state.State
+		if fullChain && block.StateRoot != "" {
+			preState = st
+		}
+		if !consensus.VerifyBlock(block, prev, preState) {
+			return failf(exitInvalid, "first inconsistency at height %d: block %s failed verification (see above)", h, block.CurrentBlockHash)
+		}
+		if fullChain {
+			if err := st.ApplyBlock(block); err != nil {
+				return failf(exitInvalid, "first inconsistency at height %d: %w", h, err)
+			}
+		}
+		prev = block
+	}
+	info := chaincheckInfo{DB: *dbPath, FromHeight: rng.BodiesFrom, ToHeight: rng.Latest, Blocks: rng.Latest - rng.BodiesFrom + 1}
+	fmt.Fprintf(w, "✅ %d blocks verified\n", info.Blocks)
+
+	if !fullChain {
+		return emit(info, func() {})
+	}
+
+	stored, err := db.LoadState()
+	if err != nil {
+		return fmt.Errorf("failed to load stored accounts: %w", err)
+	}
+	if diff := diffAccounts(stored, st); len(diff) > 0 {
+		fmt.Fprintf(w, "❌ Stored accounts differ from the ones recomputed from blocks (%d accounts):\n", len(diff))
+		for i, line := range diff {
+			if i == maxDiffLines {
+				fmt.Fprintf(w, "   ... and %d more\n", len(diff)-maxDiffLines)
+				break
+			}
+			fmt.Fprintln(w, "  ", line)
+		}
+		return failf(exitInvalid, "account state is inconsistent")
+	}
+	accounts := len(st.Accounts())
+	info.Accounts = &accounts
+	return emit(info, func() {
+		fmt.Printf("✅ %d accounts match the balances recomputed from scratch\n", accounts)
+	})
+}
+
+// diffAccounts lists every address whose stored account differs from the recomputed one
+func diffAccounts(stored, recomputed *state.State) []string {
+	addrs := make(map[string]bool)
+	for addr := range stored.Accounts() {
+		addrs[addr] = true
+	}
+	for addr := range recomputed.Accounts() {
+		addrs[addr] = true
+	}
+
+	var diff []string
+	for addr := range addrs {
+		have, _ := stored.Get(addr)
+		want, _ := recomputed.Get(addr)
+		if have.Balance.Cmp(want.Balance) == 0 && have.Nonce == want.Nonce {
+			continue
+		}
+		diff = append(diff, fmt.Sprintf("%s: stored %s (nonce %d), recomputed %s (nonce %d)",
+			wallet.DisplayAddress(addr), have.Balance.Text('f', 8), have.Nonce, want.Balance.Text('f', 8), want.Nonce))
+	}
+	sort.Strings(diff)
+	return diff
+}
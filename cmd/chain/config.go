@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang-chain/pkg/wallet"
+)
+
+// Environment variables of the CLI. The wallets directory, signer and network use
+// the variables of the wallet package, shared with the node.
+const (
+	configEnv = "CHAIN_CONFIG"
+	nodesEnv  = "CHAIN_NODES"
+	outputEnv = "CHAIN_OUTPUT"
+)
+
+// Output formats
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// defaultNodes are the nodes of the docker-compose network
+var defaultNodes = []string{"localhost:50051", "localhost:50052", "localhost:50053"}
+
+// Config is the layout of the config file, and the configuration in effect once
+// the environment and global flags are applied
+type Config struct {
+	// Nodes are asked for the leader when sending transactions; queries go to the first one
+	Nodes      []string `json:"nodes,omitempty"`
+	WalletsDir string   `json:"walletsDir,omitempty"`
+	// Signer is the address of a remote signer holding the wallet keys
+	Signer  string `json:"signer,omitempty"`
+	Network string `json:"network,omitempty"`
+	Output  string `json:"output,omitempty"`
+}
+
+// cfg is the configuration in effect, set by loadConfig
+var cfg Config
+
+// configPath is the config file that was looked for, and configLoaded whether it existed
+var (
+	configPath   string
+	configLoaded bool
+)
+
+// globalFlags holds the global flags; empty ones weren't given
+var globalFlags struct {
+	config, output, node, walletsDir, signer, network string
+}
+
+// addGlobalFlags adds the global flags to fs. They're added to the flag set of every
+// command too, so they can come before or after it; the defaults are the values
+// already parsed, so parsing one set doesn't reset what the other set.
+func addGlobalFlags(fs *flag.FlagSet) {
+	g := &globalFlags
+	fs.StringVar(&g.config, "config", g.config, "Config file (default: $"+configEnv+" or <user config dir>/golang-chain/config.json)")
+	fs.StringVar(&g.output, "output", g.output, "Output format: text or json (default: $"+outputEnv+" or text)")
+	fs.StringVar(&g.node, "node", g.node, "Node to use, host:port (default: the configured nodes, or $"+nodesEnv+")")
+	fs.StringVar(&g.walletsDir, "wallets-dir", g.walletsDir, "Wallets directory (default: $"+wallet.DirEnv+" or "+wallet.DefaultDir+")")
+	fs.StringVar(&g.signer, "signer", g.signer, "Remote signer holding the wallet keys, e.g. unix:///run/chain/signer.sock (default: $"+wallet.SignerEnv+")")
+	fs.StringVar(&g.network, "network", g.network, "Network of addresses: mainnet, testnet or devnet (default: $"+wallet.NetworkEnv+" or mainnet)")
+}
+
+// loadConfig reads the config file, then applies the environment and the global flags
+func loadConfig() error {
+	c := Config{}
+	configPath = firstOf(globalFlags.config, os.Getenv(configEnv))
+	explicit := configPath != ""
+	if !explicit {
+		if dir, err := os.UserConfigDir(); err == nil {
+			configPath = filepath.Join(dir, "golang-chain", "config.json")
+		}
+	}
+	if configPath != "" {
+		data, err := os.ReadFile(configPath)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &c); err != nil {
+				return usageError("invalid config file %s: %v", configPath, err)
+			}
+			configLoaded = true
+		case !os.IsNotExist(err) || explicit:
+			return usageError("can't read config file: %v", err)
+		}
+	}
+
+	if raw := os.Getenv(nodesEnv); raw != "" {
+		c.Nodes = splitList(raw)
+	}
+	if globalFlags.node != "" {
+		c.Nodes = []string{globalFlags.node}
+	}
+	if len(c.Nodes) == 0 {
+		c.Nodes = defaultNodes
+	}
+	c.WalletsDir = firstOf(globalFlags.walletsDir, os.Getenv(wallet.DirEnv), c.WalletsDir, wallet.DefaultDir)
+	c.Signer = firstOf(globalFlags.signer, os.Getenv(wallet.SignerEnv), c.Signer)
+	c.Network = strings.ToLower(firstOf(globalFlags.network, os.Getenv(wallet.NetworkEnv), c.Network, wallet.DefaultNetwork))
+	c.Output = strings.ToLower(firstOf(globalFlags.output, os.Getenv(outputEnv), c.Output, outputText))
+
+	if c.Output != outputText && c.Output != outputJSON {
+		return usageError("unknown output format %q (want text or json)", c.Output)
+	}
+	if _, ok := wallet.NetworkHRPs[c.Network]; !ok {
+		return usageError("unknown network %q (want mainnet, testnet or devnet)", c.Network)
+	}
+	cfg = c
+	wallet.SetDir(cfg.WalletsDir)
+	wallet.SetNetwork(cfg.Network)
+	return nil
+}
+
+// firstOf returns the first non-empty value
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// splitList splits a comma-separated list, dropping blanks
+func splitList(raw string) []string {
+	var out []string
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// configInfo is the result of the config command
+type configInfo struct {
+	File   string `json:"file"`
+	Loaded bool   `json:"loaded"`
+	Config
+}
+
+// showConfig prints the configuration in effect and where it was read from
+func showConfig(args []string) error {
+	fs := newFlagSet("config")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	info := configInfo{File: configPath, Loaded: configLoaded, Config: cfg}
+	return emit(info, func() {
+		state := "not found, using defaults"
+		if configLoaded {
+			state = "loaded"
+		}
+		fmt.Printf("⚙️  Config file:  %s (%s)\n", configPath, state)
+		fmt.Println("👉 Nodes:       ", strings.Join(cfg.Nodes, ", "))
+		fmt.Println("👉 Wallets dir: ", cfg.WalletsDir)
+		fmt.Println("👉 Signer:      ", firstOf(cfg.Signer, "none, local wallets"))
+		fmt.Println("👉 Network:     ", cfg.Network)
+		fmt.Println("👉 Output:      ", cfg.Output)
+	})
+}
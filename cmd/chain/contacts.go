@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"golang-chain/pkg/wallet"
+)
+
+var contactsCommands = []*command{
+	{name: "add", summary: "Save an address under a name", run: addContact},
+	{name: "list", summary: "List the contacts", run: listContacts},
+	{name: "remove", summary: "Remove a contact", run: removeContact},
+}
+
+// contactInfo is a contact as printed by the contacts commands
+type contactInfo struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// addContact saves an address under a name, replacing the contact's previous address
+func addContact(args []string) error {
+	fs := newFlagSet("contacts add")
+	name := fs.String("name", "", "Contact name")
+	encoded := fs.String("address", "", "Encoded address of the contact (gc1...)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" || *encoded == "" {
+		return usageError("usage: chain contacts add --name Dave --address gc1...")
+	}
+	address, err := wallet.ParseAddress(*encoded)
+	if err != nil {
+		return usageError("%v", err)
+	}
+	if old, ok := wallet.LookupContact(*name); ok && old != address {
+		fmt.Fprintf(out(), "✏️  Replacing the address of %s (was %s)\n", *name, wallet.DisplayAddress(old))
+	}
+	if err := wallet.AddContact(*name, address); err != nil {
+		return fmt.Errorf("failed to save contact: %w", err)
+	}
+	info := contactInfo{Name: *name, Address: wallet.DisplayAddress(address)}
+	return emit(info, func() {
+		fmt.Printf("✅ Contact %s saved: %s\n", info.Name, info.Address)
+	})
+}
+
+// listContacts prints the address book
+func listContacts(args []string) error {
+	fs := newFlagSet("contacts list")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	contacts, err := wallet.Contacts()
+	if err != nil {
+		return fmt.Errorf("failed to read contacts: %w", err)
+	}
+	infos := []contactInfo{}
+	for _, c := range contacts {
+		infos = append(infos, contactInfo{Name: c.Name, Address: wallet.DisplayAddress(c.Address)})
+	}
+
+	return emit(infos, func() {
+		if len(infos) == 0 {
+			fmt.Println("📭 No contacts yet, add one with: chain contacts add --name Dave --address gc1...")
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tADDRESS")
+		for _, c := range infos {
+			fmt.Fprintf(tw, "%s\t%s\n", c.Name, c.Address)
+		}
+		tw.Flush()
+	})
+}
+
+// removeContact deletes a contact
+func removeContact(args []string) error {
+	fs := newFlagSet("contacts remove")
+	name := fs.String("name", "", "Contact to remove")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return usageError("usage: chain contacts remove --name Dave")
+	}
+	address, ok := wallet.LookupContact(*name)
+	if !ok {
+		return failf(exitNotFound, "no contact named %s", *name)
+	}
+	if err := wallet.RemoveContact(*name); err != nil {
+		return err
+	}
+	info := contactInfo{Name: *name, Address: wallet.DisplayAddress(address)}
+	return emit(info, func() {
+		fmt.Printf("🗑️  Contact %s removed\n", info.Name)
+	})
+}
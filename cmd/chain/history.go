@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang-chain/pkg/p2p/pb"
+	"golang-chain/pkg/wallet"
+)
+
+// historyInfo is the result of the history command
+type historyInfo struct {
+	Account string         `json:"account"`
+	Address string         `json:"address"`
+	Total   int32          `json:"total"`
+	Offset  int            `json:"offset"`
+	Entries []historyEntry `json:"entries"`
+}
+
+type historyEntry struct {
+	Height int64  `json:"height"`
+	Index  int32  `json:"index"`
+	Time   string `json:"time"`
+	// Direction is "sent" or "received"; Counterparty is the other account, by name if known
+	Direction    string  `json:"direction"`
+	Counterparty string  `json:"counterparty"`
+	Amount       float64 `json:"amount"`
+}
+
+// history lists the transactions of an account, newest first
+func history(args []string) error {
+	fs := newFlagSet("history")
+	name := fs.String("name", "", "Wallet or contact name")
+	addressFlag := fs.String("address", "", "Account address, instead of a name")
+	offset := fs.Int("offset", 0, "Number of newest transactions to skip")
+	limit := fs.Int("limit", 10, "Maximum number of transactions to show")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" && *addressFlag == "" {
+		return usageError("usage: chain history --name Alice | --address gc1... [--offset 0 --limit 10]")
+	}
+	address, label, err := resolveAccount(*name, *addressFlag)
+	if err != nil {
+		return err
+	}
+
+	c, err := dialNode()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	resp, err := c.client.GetAccountHistory(context.Background(), &pb.AccountHistoryRequest{
+		Address: address,
+		Offset:  int32(*offset),
+		Limit:   int32(*limit),
+	})
+	if err != nil {
+		return rpcError("get history", err)
+	}
+
+	info := historyInfo{Account: firstOf(*name, wallet.ResolveAddressName(address)), Address: wallet.DisplayAddress(address), Total: resp.Total, Offset: *offset, Entries: []historyEntry{}}
+	for _, e := range resp.Entries {
+		entry := historyEntry{
+			Height:       e.Height,
+			Index:        e.Index,
+			Time:         time.Unix(e.Transaction.Timestamp, 0).Format(time.RFC3339),
+			Direction:    "received",
+			Counterparty: wallet.ResolveAddressName(e.From),
+			Amount:       e.Transaction.Amount,
+		}
+		if e.From == address {
+			entry.Direction, entry.Counterparty = "sent", wallet.ResolveAddressName(e.To)
+		}
+		info.Entries = append(info.Entries, entry)
+	}
+
+	return emit(info, func() {
+		if len(info.Entries) == 0 {
+			fmt.Printf("📭 No transactions found for %s (total: %d)\n", label, resp.Total)
+			return
+		}
+		fmt.Printf("📜 History of %s (%d-%d of %d):\n", label, *offset+1, *offset+len(info.Entries), resp.Total)
+		for _, e := range info.Entries {
+			if e.Direction == "sent" {
+				fmt.Printf("👉 #%d.%d  %s  sent %.2f coins to %s\n", e.Height, e.Index, e.Time, e.Amount, e.Counterparty)
+			} else {
+				fmt.Printf("👉 #%d.%d  %s  received %.2f coins from %s\n", e.Height, e.Index, e.Time, e.Amount, e.Counterparty)
+			}
+		}
+	})
+}
@@ -0,0 +1,123 @@
+// Command chain is the command line client of the chain: wallets, transactions,
+// multisig accounts, balances and proofs, as subcommands of one binary.
+//
+// Node endpoints, the wallets directory, the remote signer and the network are
+// shared by all commands. They come from a JSON config file, the environment and
+// global flags, in increasing order of precedence (see config.go).
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// command is a subcommand of chain. A group, like "tx", dispatches to its own subcommands.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+	sub     []*command
+}
+
+var commands = []*command{
+	{name: "status", summary: "Show the latest block of a node", run: status},
+	{name: "balance", summary: "Show the balance of an account, optionally proven", run: balance},
+	{name: "history", summary: "List the transactions of an account", run: history},
+	{name: "send", summary: "Sign and send a transaction", run: send},
+	{name: "tx", summary: "Build, sign and broadcast transactions offline", sub: txCommands},
+	{name: "multisig", summary: "Create multisig accounts and collect their signatures", sub: multisigCommands},
+	{name: "wallet", summary: "Create, restore and manage wallets", sub: walletCommands},
+	{name: "contacts", summary: "Manage the address book", sub: contactsCommands},
+	{name: "proof", summary: "Fetch and verify transaction inclusion proofs", sub: proofCommands},
+	{name: "chaincheck", summary: "Verify the database of a stopped node", run: chaincheck},
+	{name: "config", summary: "Show the configuration in effect", run: showConfig},
+}
+
+func main() {
+	os.Exit(exitCode(run(os.Args[1:])))
+}
+
+// run parses the global flags in front of the command and runs it
+func run(args []string) error {
+	fs := flag.NewFlagSet("chain", flag.ContinueOnError)
+	addGlobalFlags(fs)
+	fs.Usage = func() { printUsage("chain", commands) }
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	return dispatch("chain", commands, fs.Args())
+}
+
+// dispatch runs the command named by args[0] among cmds
+func dispatch(prefix string, cmds []*command, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(prefix, cmds)
+		if len(args) == 0 {
+			return usageError("missing command")
+		}
+		return nil
+	}
+	for _, c := range cmds {
+		if c.name != args[0] {
+			continue
+		}
+		if c.sub != nil {
+			return dispatch(prefix+" "+c.name, c.sub, args[1:])
+		}
+		return c.run(args[1:])
+	}
+	printUsage(prefix, cmds)
+	return usageError("unknown command %q", args[0])
+}
+
+func printUsage(prefix string, cmds []*command) {
+	w := os.Stderr
+	fmt.Fprintf(w, "Usage: %s <command> [flags]\n\nCommands:\n", prefix)
+	width := 0
+	for _, c := range cmds {
+		width = max(width, len(c.name))
+	}
+	for _, c := range cmds {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for the flags of a command.\n", prefix)
+	if prefix == "chain" {
+		fmt.Fprintln(w, "\nGlobal flags, accepted before or after the command:")
+		fs := flag.NewFlagSet("", flag.ContinueOnError)
+		addGlobalFlags(fs)
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+	}
+}
+
+// newFlagSet returns the flag set of a command, with the global flags added
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("chain "+name, flag.ContinueOnError)
+	addGlobalFlags(fs)
+	return fs
+}
+
+// parseFlags parses the flags of a command and loads the configuration they complete
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if fs.NArg() > 0 && !acceptsArgs[fs.Name()] {
+		return usageError("unexpected argument %q", fs.Arg(0))
+	}
+	return loadConfig()
+}
+
+// acceptsArgs lists the commands taking positional arguments besides flags
+var acceptsArgs = map[string]bool{"chain multisig combine": true}
+
+// flagError turns a flag parsing error into a usage error; -h isn't an error
+func flagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return errHelp
+	}
+	return usageError("%s", strings.TrimSpace(err.Error()))
+}
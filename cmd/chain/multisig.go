@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/keys"
+	"golang-chain/pkg/wallet"
+)
+
+var multisigCommands = []*command{
+	{name: "create", summary: "Save a multisig wallet for the given member keys", run: createMultisig},
+	{name: "propose", summary: "Write an unsigned transaction from a multisig wallet", run: proposeMultisig},
+	{name: "sign", summary: "Add the signature of one member to a transaction file", run: func(args []string) error { return signTx("multisig sign", args) }},
+	{name: "combine", summary: "Merge the member signatures of several transaction files", run: combineMultisig},
+	{name: "broadcast", summary: "Submit a fully signed transaction file", run: func(args []string) error { return broadcastTx("multisig broadcast", args) }},
+}
+
+// createMultisig saves a multisig wallet for the given member keys
+func createMultisig(args []string) error {
+	fs := newFlagSet("multisig create")
+	name := fs.String("name", "", "Name of the multisig wallet")
+	threshold := fs.Int("threshold", 0, "Number of signatures a transaction needs")
+	members := fs.String("keys", "", "Comma-separated member keys: wallet names or <scheme>:<hex public key>")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" || *members == "" || *threshold < 1 {
+		return usageError("usage: chain multisig create --name Treasury --threshold 2 --keys Alice,Bob,Carol")
+	}
+	if err := wallet.CheckName(*name); err != nil {
+		return usageError("%v", err)
+	}
+	if wallet.WalletExists(*name) {
+		return failf(exitError, "a wallet named %s already exists", *name)
+	}
+
+	var pubs []keys.PublicKey
+	for _, m := range strings.Split(*members, ",") {
+		pub, err := parseMember(strings.TrimSpace(m))
+		if err != nil {
+			return fmt.Errorf("invalid member %q: %w", m, err)
+		}
+		pubs = append(pubs, pub)
+	}
+	ms, err := keys.NewMultisigKey(*threshold, pubs)
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	path, err := wallet.SaveMultisig(*name, ms)
+	if err != nil {
+		return fmt.Errorf("failed to save the wallet: %w", err)
+	}
+	info, err := savedWallet(*name, path)
+	if err != nil {
+		return err
+	}
+	return emit(info, func() {
+		fmt.Printf("✅ The %d-of-%d multisig wallet has been saved at: %s\n", ms.Threshold(), len(ms.Members()), path)
+		fmt.Println("📫 Address:", info.Address)
+		for i, m := range ms.Members() {
+			fmt.Printf("   #%d %s\n", i, memberName(m))
+		}
+	})
+}
+
+// parseMember returns the public key of a local wallet, or decodes a <scheme>:<hex> key
+func parseMember(s string) (keys.PublicKey, error) {
+	if scheme, raw, ok := strings.Cut(s, ":"); ok {
+		sc, err := keys.ParseScheme(scheme)
+		if err != nil {
+			return nil, usageError("%v", err)
+		}
+		data, err := hex.DecodeString(raw)
+		if err != nil {
+			return nil, usageError("%v", err)
+		}
+		pub, err := keys.ParsePublicKey(sc, data)
+		if err != nil {
+			return nil, usageError("%v", err)
+		}
+		return pub, nil
+	}
+	if err := checkWallet(s); err != nil {
+		return nil, err
+	}
+	return wallet.LoadPublicKey(s)
+}
+
+// proposeMultisig writes an unsigned version 3 transaction from a multisig wallet for its
+// members to sign, with the wallet's next nonce and the minimum fee asked from the leader
+func proposeMultisig(args []string) error {
+	fs := newFlagSet("multisig propose")
+	from := fs.String("from", "", "Multisig wallet to send from")
+	to := fs.String("to", "", "Recipient wallet, contact or address")
+	amount := fs.Float64("amount", 0, "Amount of coins")
+	outFile := fs.String("out", "tx.json", "Transaction file to write")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *from == "" || *to == "" || *amount <= 0 {
+		return usageError("usage: chain multisig propose --from Treasury --to Bob --amount 10 --out tx.json")
+	}
+	if err := checkWallet(*from); err != nil {
+		return err
+	}
+	ms, err := wallet.LoadMultisig(*from)
+	if err != nil {
+		return fmt.Errorf("failed to load multisig wallet: %w", err)
+	}
+	receiver, err := wallet.ResolveAccount(*to)
+	if err != nil {
+		return accountError(err)
+	}
+
+	params, err := fetchTxParams(wallet.PublicKeyToAddress(ms))
+	if err != nil {
+		return err
+	}
+	tx := blockchain.NewNoncedTransaction(ms, []byte(receiver), *amount, params.MinFee, params.Nonce)
+	if err := writeTx(*outFile, tx); err != nil {
+		return err
+	}
+	return emit(txFileInfo{File: *outFile, Tx: newTxInfo(tx)}, func() {
+		fmt.Printf("📝 Transaction of %.2f coins from %s to %s saved to %s\n", *amount, *from, wallet.ResolveAddressName(receiver), *outFile)
+		fmt.Printf("✍️  It needs %d of %d signatures: chain multisig sign --tx %s --wallet <member>\n", ms.Threshold(), len(ms.Members()), *outFile)
+	})
+}
+
+// combineMultisig merges the member signatures of several copies of a transaction
+func combineMultisig(args []string) error {
+	fs := newFlagSet("multisig combine")
+	outFile := fs.String("out", "", "File to write the combined transaction to")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *outFile == "" || fs.NArg() < 2 {
+		return usageError("usage: chain multisig combine --out tx.json alice.json bob.json ...")
+	}
+	var txs []*blockchain.Transaction
+	for _, path := range fs.Args() {
+		tx, err := readTx(path)
+		if err != nil {
+			return err
+		}
+		txs = append(txs, tx)
+	}
+	if err := txs[0].CombineSignatures(txs[1:]...); err != nil {
+		return failf(exitInvalid, "failed to combine signatures: %w", err)
+	}
+	if err := writeTx(*outFile, txs[0]); err != nil {
+		return err
+	}
+	return emit(txFileInfo{File: *outFile, Tx: newTxInfo(txs[0])}, func() {
+		fmt.Printf("✅ Signatures of %d files combined into %s\n", len(txs), *outFile)
+		printSigners(txs[0])
+	})
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/p2p"
+	"golang-chain/pkg/p2p/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// nodeConn is a connection to a node
+type nodeConn struct {
+	conn   *grpc.ClientConn
+	client pb.NodeServiceClient
+	addr   string
+}
+
+func (c *nodeConn) Close() error { return c.conn.Close() }
+
+// dialNode connects to the node given with --node, or else the first configured one
+func dialNode() (*nodeConn, error) {
+	return dial(cfg.Nodes[0])
+}
+
+// dialLeader connects to the node given with --node, or else to the leader among
+// the configured nodes, as only the leader accepts transactions
+func dialLeader() (*nodeConn, error) {
+	if globalFlags.node != "" {
+		return dial(globalFlags.node)
+	}
+	leader := p2p.DetectLeader(cfg.Nodes)
+	if leader == "" {
+		return nil, failf(exitUnavailable, "no leader among the nodes %s", strings.Join(cfg.Nodes, ", "))
+	}
+	return dial(leader)
+}
+
+func dial(addr string) (*nodeConn, error) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, failf(exitUnavailable, "failed to connect to node %s: %w", addr, err)
+	}
+	return &nodeConn{conn: conn, client: pb.NewNodeServiceClient(conn), addr: addr}, nil
+}
+
+// rpcError wraps the error of a call to a node with the exit code matching its status
+func rpcError(what string, err error) error {
+	code := exitUnavailable
+	switch grpcstatus.Code(err) {
+	case codes.NotFound:
+		code = exitNotFound
+	case codes.InvalidArgument:
+		code = exitUsage
+	case codes.FailedPrecondition:
+		code = exitUnsupported
+	}
+	return failf(code, "failed to %s: %w", what, err)
+}
+
+// sendTransaction submits a signed transaction to the node, which must be the leader,
+// and returns the node's message once it's accepted
+func sendTransaction(c *nodeConn, tx *blockchain.Transaction) (string, error) {
+	resp, err := c.client.SendTransaction(context.Background(), p2p.TxToPb(tx))
+	if err != nil {
+		switch grpcstatus.Code(err) {
+		case codes.InvalidArgument, codes.FailedPrecondition:
+			return "", failf(exitRejected, "transaction rejected by %s: %w", c.addr, err)
+		}
+		return "", rpcError("send transaction", err)
+	}
+	if resp.Status != "ok" {
+		return "", failf(exitRejected, "transaction rejected by %s: %s", c.addr, strings.TrimPrefix(resp.Message, "❌ "))
+	}
+	return resp.Message, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Exit codes, the same for every command
+const (
+	exitOK          = 0
+	exitError       = 1 // any other failure
+	exitUsage       = 2 // invalid flags, arguments or configuration
+	exitNotFound    = 3 // a wallet, contact, file or transaction doesn't exist
+	exitUnavailable = 4 // no node could be reached, or it failed to answer
+	exitRejected    = 5 // the node rejected the transaction
+	exitInvalid     = 6 // a signature, proof or the chain failed verification
+	exitUnsupported = 7 // the node can't serve the request in its current state
+)
+
+// cliError is an error with the exit code it ends the command with
+type cliError struct {
+	code int
+	err  error
+	// reported errors were already printed, e.g. by the flag package
+	reported bool
+}
+
+func (e *cliError) Error() string { return e.err.Error() }
+func (e *cliError) Unwrap() error { return e.err }
+
+// errHelp ends a command whose help was asked for with -h
+var errHelp = &cliError{code: exitOK, err: flag.ErrHelp, reported: true}
+
+// failf returns an error ending the command with code. Like fmt.Errorf, %w wraps an error.
+func failf(code int, format string, args ...any) error {
+	return &cliError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// usageError reports invalid flags or arguments
+func usageError(format string, args ...any) error {
+	return failf(exitUsage, format, args...)
+}
+
+// exitCode prints err, unless it was already, and returns the exit code it ends the
+// command with. With --output json the error is printed as JSON on stdout, where the
+// result would have gone.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	code := exitError
+	var ce *cliError
+	if errors.As(err, &ce) {
+		code = ce.code
+		if ce.reported {
+			return code
+		}
+	}
+	if jsonOutput() {
+		printJSON(errorInfo{Error: errorDetail{Code: code, Reason: reasons[code], Message: err.Error()}})
+		return code
+	}
+	fmt.Fprintln(os.Stderr, "❌", err)
+	return code
+}
+
+// reasons name the exit codes in JSON errors
+var reasons = map[int]string{
+	exitError:       "error",
+	exitUsage:       "usage",
+	exitNotFound:    "not_found",
+	exitUnavailable: "unavailable",
+	exitRejected:    "rejected",
+	exitInvalid:     "invalid",
+	exitUnsupported: "unsupported",
+}
+
+// errorInfo is what a failed command prints with --output json, instead of its result
+type errorInfo struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// jsonOutput reports whether results are printed as JSON (--output json). Until the
+// configuration is loaded, e.g. when the flags are invalid, the flag and the
+// environment are enough to tell.
+func jsonOutput() bool {
+	output := firstOf(cfg.Output, globalFlags.output, os.Getenv(outputEnv))
+	return strings.EqualFold(output, outputJSON)
+}
+
+// out is where text meant for people goes: stdout, or stderr when the result is
+// printed as JSON so that stdout only holds the JSON
+func out() io.Writer {
+	if jsonOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// emit prints the result of a command: v as JSON with --output json, otherwise
+// whatever text prints
+func emit(v any, text func()) error {
+	if !jsonOutput() {
+		text()
+		return nil
+	}
+	return printJSON(v)
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// fileError reports a file that couldn't be read or written; a missing one wasn't found
+func fileError(what string, err error) error {
+	code := exitError
+	if errors.Is(err, fs.ErrNotExist) {
+		code = exitNotFound
+	}
+	return failf(code, "failed to %s: %w", what, err)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/p2p"
+	"golang-chain/pkg/p2p/pb"
+)
+
+var proofCommands = []*command{
+	{name: "fetch", summary: "Fetch the inclusion proof of a transaction and save it", run: fetchProof},
+	{name: "verify", summary: "Verify a saved proof offline, against a trusted header", run: verifyProof},
+}
+
+// proofFile is what gets saved by proof fetch and checked by proof verify
+type proofFile struct {
+	Header      *blockchain.BlockHeader
+	Transaction *blockchain.Transaction
+	Proof       *blockchain.MerkleProof
+}
+
+// proofInfo is the result of the proof commands
+type proofInfo struct {
+	TxHash    string `json:"txHash"`
+	Height    int64  `json:"height"`
+	BlockHash string `json:"blockHash"`
+	// File is where proof fetch saved the proof
+	File string `json:"file,omitempty"`
+	// Trusted tells whether proof verify checked against a trusted header, rather
+	// than the one in the proof file
+	Trusted bool `json:"trusted"`
+}
+
+// fetchProof asks the node for the inclusion proof of a transaction and saves it
+func fetchProof(args []string) error {
+	fs := newFlagSet("proof fetch")
+	txHash := fs.String("tx", "", "Hash of the transaction to fetch a proof for")
+	outFile := fs.String("out", "proof.json", "File to save the proof to")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *txHash == "" {
+		return usageError("usage: chain proof fetch --tx <hash> [--out proof.json]")
+	}
+
+	c, err := dialNode()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	resp, err := c.client.GetTxProof(context.Background(), &pb.TxProofRequest{TxHash: *txHash})
+	if err != nil {
+		return rpcError("get proof", err)
+	}
+
+	pf := proofFile{
+		Header:      p2p.PbToHeader(resp.Header),
+		Transaction: p2p.PbToTx(resp.Transaction),
+		Proof: &blockchain.MerkleProof{
+			TxHash:   *txHash,
+			Index:    int(resp.Index),
+			TreeSize: int(resp.TreeSize),
+			Siblings: resp.Siblings,
+		},
+	}
+	data, err := json.MarshalIndent(pf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode proof: %w", err)
+	}
+	if err := os.WriteFile(*outFile, data, 0644); err != nil {
+		return fileError("save proof", err)
+	}
+
+	info := proofInfo{TxHash: *txHash, Height: pf.Header.Height, BlockHash: pf.Header.CurrentBlockHash, File: *outFile}
+	return emit(info, func() {
+		fmt.Printf("✅ Proof for tx %s in block #%d saved to %s\n", info.TxHash, info.Height, info.File)
+	})
+}
+
+// verifyProof checks a saved proof without contacting any node.
+// Without a trusted header the header inside the proof file is used, which only
+// shows that the proof is consistent, not that the block is part of the chain.
+func verifyProof(args []string) error {
+	fs := newFlagSet("proof verify")
+	proofPath := fs.String("proof", "", "Proof file to verify")
+	headerPath := fs.String("header", "", "Trusted block header (JSON) to verify against")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *proofPath == "" {
+		return usageError("usage: chain proof verify --proof proof.json [--header header.json]")
+	}
+	var pf proofFile
+	if err := readJSON(*proofPath, &pf); err != nil {
+		return fileError("read proof", err)
+	}
+	if pf.Header == nil || pf.Transaction == nil || pf.Proof == nil {
+		return failf(exitInvalid, "proof file is incomplete")
+	}
+
+	header := pf.Header
+	if *headerPath != "" {
+		header = new(blockchain.BlockHeader)
+		if err := readJSON(*headerPath, header); err != nil {
+			return fileError("read header", err)
+		}
+		// Version 2 headers carry their own hash, older ones can only be compared
+		if header.Version >= blockchain.BlockVersion2 && blockchain.HashHeader(header) != header.CurrentBlockHash {
+			return failf(exitInvalid, "trusted header doesn't match its own hash")
+		}
+		if header.CurrentBlockHash != pf.Header.CurrentBlockHash {
+			return failf(exitInvalid, "proof is for block %s, trusted header is block %s", pf.Header.CurrentBlockHash, header.CurrentBlockHash)
+		}
+	} else {
+		fmt.Fprintln(out(), "⚠️  No trusted header given, checking against the header in the proof file")
+	}
+
+	// The proof must be about the transaction it comes with
+	hash, err := pf.Transaction.Hash()
+	if err != nil {
+		return failf(exitInvalid, "failed to hash transaction: %w", err)
+	}
+	if hex.EncodeToString(hash) != pf.Proof.TxHash {
+		return failf(exitInvalid, "transaction hash %x doesn't match the proof (%s)", hash, pf.Proof.TxHash)
+	}
+
+	ok, err := blockchain.VerifyMerkleProof(pf.Proof, header.MerkleRoot, header.Version)
+	if err != nil {
+		return failf(exitInvalid, "invalid proof: %w", err)
+	}
+	if !ok {
+		return failf(exitInvalid, "proof does NOT match Merkle root %s", header.MerkleRoot)
+	}
+
+	info := proofInfo{TxHash: pf.Proof.TxHash, Height: header.Height, BlockHash: header.CurrentBlockHash, Trusted: *headerPath != ""}
+	return emit(info, func() {
+		fmt.Printf("✅ Tx %s is included in block #%d (%s)\n", info.TxHash, info.Height, info.BlockHash)
+	})
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/wallet"
+)
+
+// send signs a version 3 transaction, with the sender's next nonce and the node's
+// minimum fee, with the sender's wallet and sends it to the leader
+func send(args []string) error {
+	fs := newFlagSet("send")
+	from := fs.String("from", "", "Sender wallet")
+	to := fs.String("to", "", "Recipient: a wallet, a contact or a gc1... address")
+	amount := fs.Float64("amount", 0, "Number of coins")
+	fee := fs.Float64("fee", -1, "Fee to pay (default: the node's minimum fee)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *from == "" || *to == "" || *amount <= 0 {
+		return usageError("usage: chain send --from Alice --to Bob --amount 10")
+	}
+	// The recipient needs no local wallet: a contact or an address will do
+	receiver, err := wallet.ResolveAccount(*to)
+	if err != nil {
+		return accountError(err)
+	}
+
+	signer, err := openSigner(*from)
+	if err != nil {
+		return err
+	}
+	defer signer.Close()
+	sender := signer.Public()
+
+	// The nonce keeps the signed transaction from being replayed once it's public
+	params, err := fetchTxParams(wallet.PublicKeyToAddress(sender))
+	if err != nil {
+		return err
+	}
+	if *fee < 0 {
+		*fee = params.MinFee
+	}
+	tx := blockchain.NewNoncedTransaction(sender, []byte(receiver), *amount, *fee, params.Nonce)
+	if err := tx.Sign(signer); err != nil {
+		return fmt.Errorf("failed to sign the transaction: %w", err)
+	}
+
+	return submit(tx)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"golang-chain/pkg/p2p/pb"
+)
+
+// statusInfo is the result of the status command
+type statusInfo struct {
+	Node     string `json:"node"`
+	Height   int64  `json:"height"`
+	Hash     string `json:"hash"`
+	PrevHash string `json:"prevHash"`
+	TxCount  int    `json:"txCount"`
+	// MerkleRoot, StateRoot and Version are the rest of the block header
+	MerkleRoot string `json:"merkleRoot"`
+	StateRoot  string `json:"stateRoot,omitempty"`
+	Version    int32  `json:"version,omitempty"`
+}
+
+// status shows the latest block of a node
+func status(args []string) error {
+	fs := newFlagSet("status")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	c, err := dialNode()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	resp, err := c.client.GetLatestBlock(context.Background(), &pb.Empty{})
+	if err != nil {
+		return rpcError("get the latest block", err)
+	}
+
+	block := resp.Block
+	info := statusInfo{
+		Node:     c.addr,
+		Height:   block.Height,
+		Hash:     block.CurrentBlockHash,
+		PrevHash: block.PrevBlockHash,
+		TxCount:  len(block.Transactions),
+
+		MerkleRoot: block.MerkleRoot,
+		StateRoot:  block.StateRoot,
+		Version:    block.Version,
+	}
+	return emit(info, func() {
+		fmt.Printf("📦 The latest block of %s:\n", c.addr)
+		fmt.Println("👉 Height:       ", info.Height)
+		fmt.Println("👉 Hash:         ", info.Hash)
+		fmt.Println("👉 Prev Hash:    ", info.PrevHash)
+		fmt.Println("👉 Tx count:     ", info.TxCount)
+	})
+}
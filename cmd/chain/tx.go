@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/keys"
+	"golang-chain/pkg/p2p/pb"
+	"golang-chain/pkg/state"
+	"golang-chain/pkg/wallet"
+)
+
+var txCommands = []*command{
+	{name: "build", summary: "Write an unsigned transaction with the sender's next nonce", run: buildTx},
+	{name: "sign", summary: "Sign a transaction file, offline with a local wallet", run: func(args []string) error { return signTx("tx sign", args) }},
+	{name: "broadcast", summary: "Submit a signed transaction file", run: func(args []string) error { return broadcastTx("tx broadcast", args) }},
+	{name: "show", summary: "Show a transaction file", run: showTx},
+}
+
+// txInfo describes a transaction, as shown by tx show
+type txInfo struct {
+	Hash string `json:"hash"`
+	// From and To are wallet or contact names when known, otherwise addresses
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Amount  float64 `json:"amount"`
+	Fee     float64 `json:"fee"`
+	Nonce   uint64  `json:"nonce"`
+	Version int32   `json:"version"`
+	Created string  `json:"created"`
+	Signed  bool    `json:"signed"`
+	// Signers and Threshold are set for a multisig sender
+	Signers   []string `json:"signers,omitempty"`
+	Threshold int      `json:"threshold,omitempty"`
+}
+
+// txFileInfo is the result of the commands writing a transaction file
+type txFileInfo struct {
+	File string `json:"file"`
+	Tx   txInfo `json:"tx"`
+}
+
+func newTxInfo(tx *blockchain.Transaction) txInfo {
+	hash, _ := tx.Hash()
+	info := txInfo{
+		Hash:    hex.EncodeToString(hash),
+		To:      wallet.ResolveAddressName(string(tx.Receiver)),
+		Amount:  tx.Amount,
+		Fee:     tx.Fee,
+		Nonce:   tx.Nonce,
+		Version: tx.Version,
+		Created: time.Unix(tx.Timestamp, 0).Format(time.RFC3339),
+		Signed:  len(tx.Signature) > 0,
+	}
+	if from, _, err := state.TxAccounts(tx); err == nil {
+		info.From = wallet.ResolveAddressName(from)
+	} else {
+		// The sender of an unsigned version 2 transaction isn't known yet
+		info.From = "unknown, " + err.Error()
+	}
+	if signed, threshold, err := tx.Signers(); err == nil {
+		info.Threshold = threshold
+		info.Signers = []string{}
+		for _, pub := range signed {
+			info.Signers = append(info.Signers, memberName(pub))
+		}
+		info.Signed = len(signed) >= threshold
+	}
+	return info
+}
+
+// readTx reads a transaction file
+func readTx(path string) (*blockchain.Transaction, error) {
+	tx, err := blockchain.ReadTxFile(path)
+	if err != nil {
+		return nil, fileError("read "+path, err)
+	}
+	return tx, nil
+}
+
+// writeTx writes a transaction file
+func writeTx(path string, tx *blockchain.Transaction) error {
+	if err := blockchain.WriteTxFile(path, tx); err != nil {
+		return fileError("write transaction file", err)
+	}
+	return nil
+}
+
+// buildTx writes an unsigned version 3 transaction, with the sender's next nonce and
+// the minimum fee asked from the leader. It only needs the sender's public key.
+func buildTx(args []string) error {
+	fs := newFlagSet("tx build")
+	from := fs.String("from", "", "Sender wallet name")
+	to := fs.String("to", "", "Recipient wallet, contact or address")
+	amount := fs.Float64("amount", 0, "Amount of coins")
+	fee := fs.Float64("fee", -1, "Fee to pay (default: the node's minimum fee)")
+	nonce := fs.Int64("nonce", -1, "Nonce of the transaction (default: the sender's next nonce)")
+	outFile := fs.String("out", "tx.json", "Transaction file to write")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *from == "" || *to == "" || *amount <= 0 {
+		return usageError("usage: chain tx build --from Alice --to Bob --amount 10 --out tx.json")
+	}
+	if err := checkWallet(*from); err != nil {
+		return err
+	}
+	sender, err := wallet.LoadPublicKey(*from)
+	if err != nil {
+		return fmt.Errorf("failed to load wallet: %w", err)
+	}
+	receiver, err := wallet.ResolveAccount(*to)
+	if err != nil {
+		return accountError(err)
+	}
+
+	if *fee < 0 || *nonce < 0 {
+		params, err := fetchTxParams(wallet.PublicKeyToAddress(sender))
+		if err != nil {
+			return err
+		}
+		if *fee < 0 {
+			*fee = params.MinFee
+		}
+		if *nonce < 0 {
+			*nonce = int64(params.Nonce)
+		}
+	}
+
+	tx := blockchain.NewNoncedTransaction(sender, []byte(receiver), *amount, *fee, uint64(*nonce))
+	if err := writeTx(*outFile, tx); err != nil {
+		return err
+	}
+	return emit(txFileInfo{File: *outFile, Tx: newTxInfo(tx)}, func() {
+		fmt.Println("📝 Unsigned transaction saved to", *outFile)
+		printTx(tx)
+		fmt.Printf("✍️  Sign it with: chain tx sign --tx %s --wallet <signer>\n", *outFile)
+	})
+}
+
+// fetchTxParams asks the leader, or --node, for the next nonce of address and the minimum fee
+func fetchTxParams(address string) (*pb.TxParams, error) {
+	c, err := dialLeader()
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	params, err := c.client.GetTxParams(context.Background(), &pb.TxParamsRequest{Address: address})
+	if err != nil {
+		return nil, rpcError("get nonce and fee", err)
+	}
+	return params, nil
+}
+
+// signTx signs a transaction file with a local wallet, or a remote signer holding its
+// key. With a local wallet it needs no network access.
+// For a multisig sender the signature is added to those already in the file.
+func signTx(name string, args []string) error {
+	fs := newFlagSet(name)
+	path := fs.String("tx", "", "Transaction file to sign")
+	walletName := fs.String("wallet", "", "Wallet to sign with")
+	outFile := fs.String("out", "", "File to write the signed transaction to (default: overwrite --tx)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *path == "" || *walletName == "" {
+		return usageError("usage: chain %s --tx tx.json --wallet Alice", name)
+	}
+	if *outFile == "" {
+		*outFile = *path
+	}
+	tx, err := readTx(*path)
+	if err != nil {
+		return err
+	}
+	// Show what is being signed, the file may come from an untrusted machine
+	printTx(tx)
+
+	signer, err := openSigner(*walletName)
+	if err != nil {
+		return err
+	}
+	defer signer.Close()
+	if err := tx.Sign(signer); err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := writeTx(*outFile, tx); err != nil {
+		return err
+	}
+	return emit(txFileInfo{File: *outFile, Tx: newTxInfo(tx)}, func() {
+		fmt.Printf("✅ Signed by %s, saved to %s\n", *walletName, *outFile)
+		printSigners(tx)
+	})
+}
+
+// broadcastTx submits a signed transaction file to the leader, or --node
+func broadcastTx(name string, args []string) error {
+	fs := newFlagSet(name)
+	path := fs.String("tx", "", "Signed transaction file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return usageError("usage: chain %s --tx signed.json", name)
+	}
+	tx, err := readTx(*path)
+	if err != nil {
+		return err
+	}
+	ok, err := tx.Verify()
+	if err != nil {
+		return failf(exitInvalid, "invalid transaction: %w", err)
+	}
+	if !ok {
+		printSigners(tx)
+		return failf(exitInvalid, "the transaction isn't fully signed, or has an invalid signature")
+	}
+
+	return submit(tx)
+}
+
+// submitInfo is the result of submitting a transaction
+type submitInfo struct {
+	TxHash  string `json:"txHash"`
+	Node    string `json:"node"`
+	Message string `json:"message"`
+}
+
+// submit sends a signed transaction to the leader, or --node, and prints its hash
+func submit(tx *blockchain.Transaction) error {
+	hash, err := tx.Hash()
+	if err != nil {
+		return fmt.Errorf("failed to hash the transaction: %w", err)
+	}
+
+	c, err := dialLeader()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	msg, err := sendTransaction(c, tx)
+	if err != nil {
+		return err
+	}
+	info := submitInfo{TxHash: hex.EncodeToString(hash), Node: c.addr, Message: msg}
+	return emit(info, func() {
+		fmt.Println("📨", info.Message)
+		fmt.Println("🔖 Tx hash:", info.TxHash)
+	})
+}
+
+// showTx prints a transaction file
+func showTx(args []string) error {
+	fs := newFlagSet("tx show")
+	path := fs.String("tx", "", "Transaction file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return usageError("usage: chain tx show --tx tx.json")
+	}
+	tx, err := readTx(*path)
+	if err != nil {
+		return err
+	}
+	return emit(newTxInfo(tx), func() {
+		printTx(tx)
+		printSigners(tx)
+	})
+}
+
+// printTx shows the fields of a transaction
+func printTx(tx *blockchain.Transaction) {
+	info := newTxInfo(tx)
+	w := out()
+	fmt.Fprintln(w, "👉 From:     ", info.From)
+	fmt.Fprintln(w, "👉 To:       ", info.To)
+	fmt.Fprintf(w, "👉 Amount:    %.8f\n", info.Amount)
+	fmt.Fprintf(w, "👉 Fee:       %.8f\n", info.Fee)
+	fmt.Fprintln(w, "👉 Nonce:    ", info.Nonce)
+	fmt.Fprintln(w, "👉 Version:  ", info.Version)
+	fmt.Fprintln(w, "👉 Created:  ", info.Created)
+	fmt.Fprintln(w, "👉 Hash:     ", info.Hash)
+}
+
+// printSigners shows whether the transaction is signed, and by whom for a multisig
+// sender along with whether it can be broadcast
+func printSigners(tx *blockchain.Transaction) {
+	info := newTxInfo(tx)
+	w := out()
+	switch {
+	case info.Threshold > 0:
+		fmt.Fprintf(w, "✍️  %d of %d required signatures: %s\n", len(info.Signers), info.Threshold, strings.Join(info.Signers, ", "))
+		if info.Signed {
+			fmt.Fprintln(w, "🚀 Ready to broadcast")
+		}
+	case info.Signed:
+		fmt.Fprintln(w, "✍️  Signed")
+	default:
+		fmt.Fprintln(w, "✍️  Not signed yet")
+	}
+}
+
+// memberName shows a key by its wallet name, or its scheme and key
+func memberName(pub keys.PublicKey) string {
+	if name := wallet.ResolveSenderName(pub); name != "Unknown" {
+		return name
+	}
+	return fmt.Sprintf("%s:%x", pub.Scheme(), pub.Bytes())
+}
@@ -0,0 +1,615 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"golang-chain/pkg/keys"
+	"golang-chain/pkg/wallet"
+
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+var walletCommands = []*command{
+	{name: "create", summary: "Create a wallet, or an HD wallet with --mnemonic", run: createWallet},
+	{name: "restore", summary: "Restore an HD wallet from its recovery phrase", run: restoreWallet},
+	{name: "encrypt", summary: "Encrypt plaintext wallets with a passphrase", run: encryptWallets},
+	{name: "list", summary: "List the wallets with their scheme and address", run: listWallets},
+	{name: "show", summary: "Show the public key and address of a wallet", run: showWallet},
+	{name: "import", summary: "Import a PEM private key or a recovery phrase", run: importWallet},
+	{name: "export", summary: "Export the public information of a wallet as JSON", run: exportWallet},
+	{name: "rename", summary: "Rename a wallet", run: renameWallet},
+	{name: "delete", summary: "Delete a wallet", run: deleteWallet},
+}
+
+// checkWallet fails if there's no wallet named name
+func checkWallet(name string) error {
+	if !wallet.WalletExists(name) {
+		return failf(exitNotFound, "wallet %s does not exist in %s", name, wallet.Dir())
+	}
+	return nil
+}
+
+// checkNewAccounts fails if any of the first n accounts of an HD wallet already exists
+func checkNewAccounts(name string, n uint32) error {
+	for i := uint32(0); i < n; i++ {
+		if wallet.WalletExists(wallet.AccountName(name, i)) {
+			return failf(exitError, "a wallet named %s already exists", wallet.AccountName(name, i))
+		}
+	}
+	return nil
+}
+
+// openSigner opens the key of a wallet, on the configured remote signer if there's one
+func openSigner(name string) (wallet.Signer, error) {
+	// With a remote signer the wallet file may only exist on the signer
+	if cfg.Signer == "" {
+		if err := checkWallet(name); err != nil {
+			return nil, err
+		}
+	}
+	signer, err := wallet.OpenSigner(name, cfg.Signer)
+	if err != nil {
+		code := exitError
+		switch grpcstatus.Code(err) {
+		case codes.NotFound:
+			code = exitNotFound
+		case codes.Unavailable:
+			code = exitUnavailable
+		}
+		return nil, failf(code, "failed to load wallet %s: %w", name, err)
+	}
+	return signer, nil
+}
+
+// loadInfo reads the public information of a wallet
+func loadInfo(name string) (*wallet.Info, error) {
+	if err := checkWallet(name); err != nil {
+		return nil, err
+	}
+	info, err := wallet.WalletInfo(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet: %w", err)
+	}
+	return info, nil
+}
+
+// savedWalletInfo is the result of the commands saving a wallet
+type savedWalletInfo struct {
+	*wallet.Info
+	File string `json:"file"`
+}
+
+// savedWallet returns the public information of a wallet just saved to file
+func savedWallet(name, file string) (*savedWalletInfo, error) {
+	info, err := wallet.WalletInfo(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the saved wallet: %w", err)
+	}
+	return &savedWalletInfo{Info: info, File: file}, nil
+}
+
+// hdWalletInfo is the result of the commands saving the accounts of an HD wallet
+type hdWalletInfo struct {
+	Accounts []*savedWalletInfo `json:"accounts"`
+	// Mnemonic is only set for a new wallet, whose recovery phrase has to be written down
+	Mnemonic string `json:"mnemonic,omitempty"`
+}
+
+// savedAccounts returns the public information of the HD accounts just saved
+func savedAccounts(name string, n int) (*hdWalletInfo, error) {
+	info := &hdWalletInfo{}
+	for i := 0; i < n; i++ {
+		account := wallet.AccountName(name, uint32(i))
+		saved, err := savedWallet(account, wallet.FilePath(account))
+		if err != nil {
+			return nil, err
+		}
+		info.Accounts = append(info.Accounts, saved)
+	}
+	return info, nil
+}
+
+// parseScheme parses the --scheme flag
+func parseScheme(name string) (keys.Scheme, error) {
+	scheme, err := keys.ParseScheme(name)
+	if err != nil {
+		return 0, usageError("%v", err)
+	}
+	return scheme, nil
+}
+
+// createWallet creates a wallet, or with --mnemonic an HD wallet and its first accounts
+func createWallet(args []string) error {
+	fs := newFlagSet("wallet create")
+	name := fs.String("name", "", "Wallet name (e.g. Alice, Bob)")
+	mnemonic := fs.Bool("mnemonic", false, "Create an HD wallet backed by a 24-word recovery phrase")
+	accounts := fs.Uint("accounts", 1, "Number of accounts to derive for an HD wallet")
+	schemeName := fs.String("scheme", keys.DefaultScheme.String(), "Signature scheme: p256, secp256k1 or ed25519")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return usageError("usage: chain wallet create --name Alice [--scheme p256] [--mnemonic --accounts 3]")
+	}
+	if err := wallet.CheckName(*name); err != nil {
+		return usageError("%v", err)
+	}
+	scheme, err := parseScheme(*schemeName)
+	if err != nil {
+		return err
+	}
+
+	if *mnemonic {
+		if *accounts == 0 {
+			return usageError("--accounts must be at least 1")
+		}
+		if err := checkNewAccounts(*name, uint32(*accounts)); err != nil {
+			return err
+		}
+	} else if wallet.WalletExists(*name) {
+		return failf(exitError, "a wallet named %s already exists", *name)
+	}
+
+	passphrase, err := wallet.NewPassphrase()
+	if err != nil {
+		return err
+	}
+
+	if *mnemonic {
+		return createHDWallet(*name, scheme, uint32(*accounts), passphrase)
+	}
+
+	w, err := wallet.NewWalletWithScheme(scheme)
+	if err != nil {
+		return fmt.Errorf("failed to create the wallet: %w", err)
+	}
+	filePath, err := wallet.SaveWallet(*name, w, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to save the wallet: %w", err)
+	}
+	info, err := savedWallet(*name, filePath)
+	if err != nil {
+		return err
+	}
+	return emit(info, func() {
+		fmt.Println("✅ The wallet has been created and saved at:", filePath)
+		fmt.Println("📫 Address:", info.Address)
+	})
+}
+
+// createHDWallet generates a new mnemonic and saves the first n accounts derived from it
+func createHDWallet(name string, scheme keys.Scheme, n uint32, passphrase string) error {
+	phrase, err := wallet.NewMnemonic()
+	if err != nil {
+		return fmt.Errorf("failed to create the wallet: %w", err)
+	}
+	if _, err := wallet.SaveHDAccounts(name, scheme, phrase, n, passphrase); err != nil {
+		return fmt.Errorf("failed to save the wallet: %w", err)
+	}
+	info, err := savedAccounts(name, int(n))
+	if err != nil {
+		return err
+	}
+	info.Mnemonic = phrase
+
+	return emit(info, func() {
+		fmt.Println("✅ The HD wallet has been created.")
+		printAccounts(info)
+		fmt.Println()
+		fmt.Println("📝 Recovery phrase, write it down and keep it safe. Anyone who has it controls these accounts:")
+		fmt.Println()
+		fmt.Println("  ", phrase)
+		fmt.Println()
+		fmt.Printf("🔁 Restore it with: chain wallet restore --name %s --scheme %s --accounts %d\n", name, scheme, n)
+	})
+}
+
+// printAccounts lists the accounts of an HD wallet with their path and address
+func printAccounts(info *hdWalletInfo) {
+	for _, a := range info.Accounts {
+		fmt.Printf("   %-12s %s  %s\n", a.Name, a.Path, a.Address)
+	}
+}
+
+// restoreWallet derives the accounts of an HD wallet again from its recovery phrase
+func restoreWallet(args []string) error {
+	fs := newFlagSet("wallet restore")
+	name := fs.String("name", "", "Name to save the restored wallet under (e.g. Alice)")
+	phrase := fs.String("mnemonic", "", "Recovery phrase; read from stdin if not given")
+	accounts := fs.Uint("accounts", 1, "Number of accounts to derive")
+	force := fs.Bool("force", false, "Overwrite existing wallet files with the same name")
+	schemeName := fs.String("scheme", keys.DefaultScheme.String(), "Signature scheme the wallet was created with: p256, secp256k1 or ed25519")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" || *accounts == 0 {
+		return usageError(`usage: chain wallet restore --name Alice [--scheme p256] [--accounts 3] [--mnemonic "word1 word2 ..."]`)
+	}
+	if err := wallet.CheckName(*name); err != nil {
+		return usageError("%v", err)
+	}
+	scheme, err := parseScheme(*schemeName)
+	if err != nil {
+		return err
+	}
+
+	if *phrase == "" {
+		// Reading the phrase from stdin keeps it out of the shell history
+		line, err := wallet.ReadSecret("🔑 Enter the recovery phrase: ")
+		if err != nil {
+			return fmt.Errorf("failed to read the recovery phrase: %w", err)
+		}
+		*phrase = line
+	}
+	if !wallet.ValidMnemonic(*phrase) {
+		return failf(exitInvalid, "invalid recovery phrase: check the words and their order")
+	}
+	if !*force {
+		if err := checkNewAccounts(*name, uint32(*accounts)); err != nil {
+			return fmt.Errorf("%w, use --force to overwrite it", err)
+		}
+	}
+
+	passphrase, err := wallet.NewPassphrase()
+	if err != nil {
+		return err
+	}
+	if _, err := wallet.SaveHDAccounts(*name, scheme, *phrase, uint32(*accounts), passphrase); err != nil {
+		return fmt.Errorf("failed to restore the wallet: %w", err)
+	}
+	info, err := savedAccounts(*name, int(*accounts))
+	if err != nil {
+		return err
+	}
+	return emit(info, func() {
+		fmt.Println("✅ The HD wallet has been restored.")
+		printAccounts(info)
+	})
+}
+
+// encryptWallets encrypts a wallet, or every plaintext wallet, with one passphrase
+func encryptWallets(args []string) error {
+	fs := newFlagSet("wallet encrypt")
+	name := fs.String("name", "", "Wallet to encrypt; all plaintext wallets if not given")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	names := []string{*name}
+	if *name == "" {
+		all, err := wallet.ListWallets()
+		if err != nil {
+			return fmt.Errorf("failed to list wallets: %w", err)
+		}
+		names = names[:0]
+		for _, n := range all {
+			// Multisig wallets only hold public keys, there's nothing to encrypt
+			if encrypted, err := wallet.IsEncrypted(n); err == nil && !encrypted && !wallet.IsMultisig(n) {
+				names = append(names, n)
+			}
+		}
+		if len(names) == 0 {
+			return emit(encryptInfo{Encrypted: []string{}}, func() {
+				fmt.Println("✅ No plaintext wallets left to encrypt.")
+			})
+		}
+	} else if err := checkWallet(*name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out(), "🔐 Encrypting %d wallet(s) with one passphrase\n", len(names))
+	passphrase, err := wallet.NewPassphrase()
+	if err != nil {
+		return err
+	}
+
+	// Progress goes to out() as it happens; the result only lists the encrypted wallets
+	info := encryptInfo{Encrypted: []string{}}
+	failed := 0
+	for _, n := range names {
+		if err := wallet.EncryptWallet(n, passphrase); err != nil {
+			fmt.Fprintf(out(), "❌ %s: %v\n", n, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out(), "✅ %s encrypted\n", n)
+		info.Encrypted = append(info.Encrypted, n)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d wallet(s) could not be encrypted", failed)
+	}
+	return emit(info, func() {})
+}
+
+// encryptInfo is the result of the wallet encrypt command
+type encryptInfo struct {
+	Encrypted []string `json:"encrypted"`
+}
+
+// listWallets prints every wallet with its scheme and address
+func listWallets(args []string) error {
+	fs := newFlagSet("wallet list")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	names, err := wallet.ListWallets()
+	if err != nil {
+		return fmt.Errorf("failed to list wallets: %w", err)
+	}
+	infos := []*wallet.Info{}
+	for _, name := range names {
+		info, err := wallet.WalletInfo(name)
+		if err != nil {
+			fmt.Fprintf(out(), "⚠️  Wallet %s is unreadable: %v\n", name, err)
+			continue
+		}
+		infos = append(infos, info)
+	}
+
+	return emit(infos, func() {
+		if len(infos) == 0 {
+			fmt.Println("📭 No wallets in", wallet.Dir())
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tSCHEME\tADDRESS\tNOTES")
+		for _, info := range infos {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, info.Scheme, info.Address, notes(info))
+		}
+		tw.Flush()
+	})
+}
+
+// notes summarizes how a wallet is stored
+func notes(info *wallet.Info) string {
+	var out []string
+	switch {
+	case info.Threshold > 0:
+		out = append(out, fmt.Sprintf("%d-of-%d", info.Threshold, len(info.Members)))
+	case info.Encrypted:
+		out = append(out, "encrypted")
+	default:
+		out = append(out, "⚠️ plaintext")
+	}
+	if info.Path != "" {
+		out = append(out, info.Path)
+	}
+	return strings.Join(out, ", ")
+}
+
+// showWallet prints the public key and address of a wallet
+func showWallet(args []string) error {
+	fs := newFlagSet("wallet show")
+	name := fs.String("name", "", "Wallet to show")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return usageError("usage: chain wallet show --name Alice")
+	}
+	info, err := loadInfo(*name)
+	if err != nil {
+		return err
+	}
+
+	return emit(info, func() {
+		address, err := wallet.ParseAddress(info.Address)
+		if err != nil {
+			// The address couldn't be encoded, e.g. because the network is invalid
+			address = info.Address
+		}
+		fmt.Println("👛 Wallet:    ", info.Name)
+		fmt.Println("🔐 Scheme:    ", info.Scheme)
+		fmt.Println("📫 Address:   ", info.Address)
+		fmt.Println("🏷️  Raw address:", address)
+		fmt.Println("🔑 Public key:", info.PublicKey)
+		if info.Path != "" {
+			fmt.Println("🌱 HD path:   ", info.Path)
+		}
+		if info.Threshold > 0 {
+			fmt.Printf("👥 Multisig:   %d of %d\n", info.Threshold, len(info.Members))
+			for i, m := range info.Members {
+				fmt.Printf("   #%d %s\n", i, m)
+			}
+		} else {
+			fmt.Println("🛡️  Encrypted: ", info.Encrypted)
+		}
+	})
+}
+
+// importWallet saves a wallet from a PEM private key or from a recovery phrase
+func importWallet(args []string) error {
+	fs := newFlagSet("wallet import")
+	name := fs.String("name", "", "Name to save the wallet under")
+	pemFile := fs.String("pem", "", "PEM file holding an unencrypted P-256 or Ed25519 private key")
+	mnemonic := fs.Bool("mnemonic", false, "Import an HD wallet from a recovery phrase, read from stdin")
+	schemeName := fs.String("scheme", keys.DefaultScheme.String(), "Signature scheme of the HD wallet: p256, secp256k1 or ed25519")
+	accounts := fs.Uint("accounts", 1, "Number of HD accounts to derive")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" || (*pemFile == "") == !*mnemonic {
+		return usageError("usage: chain wallet import --name Alice --pem alice.pem | --mnemonic")
+	}
+	if err := wallet.CheckName(*name); err != nil {
+		return usageError("%v", err)
+	}
+
+	if *mnemonic {
+		return importMnemonic(*name, *schemeName, uint32(*accounts))
+	}
+
+	if wallet.WalletExists(*name) {
+		return failf(exitError, "a wallet named %s already exists", *name)
+	}
+	data, err := os.ReadFile(*pemFile)
+	if err != nil {
+		return fileError("read the private key", err)
+	}
+	priv, err := wallet.ParsePEMPrivateKey(data)
+	if err != nil {
+		return failf(exitInvalid, "invalid private key: %w", err)
+	}
+	passphrase, err := wallet.NewPassphrase()
+	if err != nil {
+		return err
+	}
+	path, err := wallet.SaveWallet(*name, &wallet.Wallet{PrivateKey: priv, PublicKey: priv.Public()}, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to save the wallet: %w", err)
+	}
+	info, err := savedWallet(*name, path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out(), "🧹 %s still holds the key in plaintext, delete it once the wallet is backed up\n", *pemFile)
+	return emit(info, func() {
+		fmt.Println("✅ The wallet has been imported at:", path)
+		fmt.Println("📫 Address:", info.Address)
+	})
+}
+
+// importMnemonic derives and saves the first accounts of an HD wallet
+func importMnemonic(name, schemeName string, accounts uint32) error {
+	scheme, err := parseScheme(schemeName)
+	if err != nil {
+		return err
+	}
+	if accounts == 0 {
+		return usageError("--accounts must be at least 1")
+	}
+	if err := checkNewAccounts(name, accounts); err != nil {
+		return err
+	}
+
+	// Reading the phrase from stdin keeps it out of the shell history
+	phrase, err := wallet.ReadSecret("🔑 Enter the recovery phrase: ")
+	if err != nil {
+		return fmt.Errorf("failed to read the recovery phrase: %w", err)
+	}
+	if !wallet.ValidMnemonic(phrase) {
+		return failf(exitInvalid, "invalid recovery phrase: check the words and their order")
+	}
+	passphrase, err := wallet.NewPassphrase()
+	if err != nil {
+		return err
+	}
+
+	if _, err := wallet.SaveHDAccounts(name, scheme, phrase, accounts, passphrase); err != nil {
+		return fmt.Errorf("failed to import the wallet: %w", err)
+	}
+	info, err := savedAccounts(name, int(accounts))
+	if err != nil {
+		return err
+	}
+	return emit(info, func() {
+		fmt.Println("✅ The HD wallet has been imported.")
+		printAccounts(info)
+	})
+}
+
+// exportWallet writes the public information of a wallet as JSON, e.g. to set up a
+// multisig account or a watch-only machine. It never includes the private key.
+func exportWallet(args []string) error {
+	fs := newFlagSet("wallet export")
+	name := fs.String("name", "", "Wallet to export")
+	outFile := fs.String("out", "", "File to write to (default: stdout)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return usageError("usage: chain wallet export --name Alice [--out alice.json]")
+	}
+	info, err := loadInfo(*name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *outFile == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*outFile, data, 0o644); err != nil {
+		return fileError("write the export", err)
+	}
+	return emit(&savedWalletInfo{Info: info, File: *outFile}, func() {
+		fmt.Printf("✅ Public information of %s saved to %s\n", *name, *outFile)
+	})
+}
+
+// renameWallet gives a wallet a new name
+func renameWallet(args []string) error {
+	fs := newFlagSet("wallet rename")
+	name := fs.String("name", "", "Wallet to rename")
+	to := fs.String("to", "", "New name")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" || *to == "" {
+		return usageError("usage: chain wallet rename --name Alice --to Alice2")
+	}
+	if err := checkWallet(*name); err != nil {
+		return err
+	}
+	if err := wallet.RenameWallet(*name, *to); err != nil {
+		return err
+	}
+	info, err := loadInfo(*to)
+	if err != nil {
+		return err
+	}
+	return emit(info, func() {
+		fmt.Printf("✅ Wallet %s renamed to %s\n", *name, *to)
+	})
+}
+
+// deleteWallet deletes a wallet file after confirmation
+func deleteWallet(args []string) error {
+	fs := newFlagSet("wallet delete")
+	name := fs.String("name", "", "Wallet to delete")
+	yes := fs.Bool("yes", false, "Don't ask for confirmation")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return usageError("usage: chain wallet delete --name Alice [--yes]")
+	}
+	info, err := loadInfo(*name)
+	if err != nil {
+		return err
+	}
+	if !*yes {
+		if info.Threshold == 0 {
+			fmt.Fprintln(os.Stderr, "⚠️  The private key of this wallet is lost unless you have a backup or its recovery phrase.")
+		}
+		fmt.Fprintf(os.Stderr, "Type the wallet name (%s) to delete it: ", *name)
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(line) != *name {
+			return fmt.Errorf("aborted")
+		}
+	}
+	if err := wallet.DeleteWallet(*name); err != nil {
+		return err
+	}
+	return emit(info, func() {
+		fmt.Printf("🗑️  Wallet %s (%s) deleted\n", *name, info.Address)
+	})
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"golang-chain/pkg/archive"
+	"golang-chain/pkg/storage"
+)
+
+// runCommand runs a maintenance subcommand on the opened database instead of starting the node:
+//
+//	go-blockchain export --file chain.archive
+//	go-blockchain import --file chain.archive
+func runCommand(db *storage.DB, args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	file := fs.String("file", "chain.archive", "Archive file")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "export":
+		n, err := archive.ExportFile(db, *file)
+		if err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
+		log.Printf("✅ Exported %d blocks to %s", n, *file)
+	case "import":
+		n, err := archive.ImportFile(db, *file)
+		if err != nil {
+			return fmt.Errorf("import failed after %d blocks: %w", n, err)
+		}
+		log.Printf("✅ Imported %d blocks from %s", n, *file)
+	default:
+		return fmt.Errorf("unknown command %q (available: export, import)", args[0])
+	}
+	return nil
+}
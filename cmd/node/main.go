@@ -1,18 +1,24 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"golang-chain/pkg/blockchain"
 	"golang-chain/pkg/p2p"
 	"golang-chain/pkg/storage"
+	"golang-chain/pkg/wallet"
 )
 
 func main() {
+	reindex := flag.Bool("reindex", false, "Rebuild balances and indexes from the stored blocks before starting")
+	flag.Parse()
+
 	fmt.Println("Hello from validator node!")
 
 	port := os.Getenv("PORT")
@@ -30,17 +36,140 @@ func main() {
 		dbPath = "data/" + nodeID
 	}
 
+	dbEngine := os.Getenv("DB_ENGINE")
+	if dbEngine == "" {
+		dbEngine = storage.EngineLevelDB
+	}
+
+	snapshotInterval := int64(100)
+	if raw := os.Getenv("SNAPSHOT_INTERVAL"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			log.Fatalln("❌ Invalid SNAPSHOT_INTERVAL:", err)
+		}
+		snapshotInterval = n
+	}
+
+	fastSync := os.Getenv("FAST_SYNC") == "true"
+	var checkpoint *p2p.Checkpoint
+	if raw := os.Getenv("FAST_SYNC_CHECKPOINT"); raw != "" {
+		c, err := p2p.ParseCheckpoint(raw)
+		if err != nil {
+			log.Fatalln("❌ Invalid FAST_SYNC_CHECKPOINT:", err)
+		}
+		checkpoint = c
+	}
+
+	// Pruning is off unless a number of blocks to keep is given
+	var pruneKeep int64
+	if raw := os.Getenv("PRUNE_KEEP_BLOCKS"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			log.Fatalln("❌ Invalid PRUNE_KEEP_BLOCKS:", err)
+		}
+		pruneKeep = n
+	}
+	pruneInterval := time.Minute
+	if raw := os.Getenv("PRUNE_INTERVAL"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalln("❌ Invalid PRUNE_INTERVAL:", err)
+		}
+		pruneInterval = d
+	}
+
+	if raw := os.Getenv("MIN_TX_FEE"); raw != "" {
+		fee, err := strconv.ParseFloat(raw, 64)
+		if err != nil || fee < 0 {
+			log.Fatalln("❌ Invalid MIN_TX_FEE:", raw)
+		}
+		p2p.MinTxFee = fee
+	}
+
+	// Proposals and votes are signed with the validator key, from the local keystore
+	// or from a remote signer so the key can live in a separate process
+	if name := os.Getenv("VALIDATOR_KEY"); name != "" {
+		signer, err := wallet.OpenSigner(name, os.Getenv(wallet.SignerEnv))
+		if err != nil {
+			log.Fatalln("❌ Failed to open validator key:", err)
+		}
+		defer signer.Close()
+		p2p.ValidatorSigner = signer
+		log.Println("🔏 Signing proposals and votes as", wallet.DisplayAddress(signer.Public().Address()))
+	}
+	if raw := os.Getenv("VALIDATORS"); raw != "" {
+		p2p.Validators = make(map[string]bool)
+		for _, a := range strings.Split(raw, ",") {
+			address, err := wallet.ParseAddress(strings.TrimSpace(a))
+			if err != nil {
+				log.Fatalf("❌ Invalid validator address %q: %v", a, err)
+			}
+			p2p.Validators[address] = true
+		}
+	}
+	// Without validators anyone can propose and vote, so that has to be asked for
+	p2p.AllowUnsigned = os.Getenv("ALLOW_UNSIGNED_VOTES") == "true"
+	if p2p.AllowUnsigned && len(p2p.Validators) > 0 {
+		log.Fatalln("❌ ALLOW_UNSIGNED_VOTES can't be combined with VALIDATORS")
+	}
+
 	var peers []string
 	if raw := os.Getenv("PEERS"); raw != "" {
 		peers = strings.Split(raw, ",")
 	}
 
-	db, err := storage.NewDB(dbPath)
+	db, err := storage.OpenDB(dbEngine, dbPath)
 	if err != nil {
 		log.Fatalln("❌ Failed to open DB:", err)
 	}
 	defer db.Close()
 
+	if err := db.Migrate(); err != nil {
+		log.Fatalln("❌ Failed to migrate DB:", err)
+	}
+
+	if err := db.CheckConsistency(); err != nil {
+		log.Fatalln("❌ Database is inconsistent:", err)
+	}
+	// A reindex that was interrupted is resumed even without the flag
+	reindexing, err := db.Reindexing()
+	if err != nil {
+		log.Fatalln("❌ Failed to read DB:", err)
+	}
+	if *reindex || reindexing {
+		log.Println("🔁 Reindexing: rebuilding balances and indexes from stored blocks...")
+		err := db.Reindex(func(height, latest int64) {
+			log.Printf("🔁 Replayed blocks up to height %d/%d (%d%%)", height, latest, (height+1)*100/(latest+1))
+		})
+		if err != nil {
+			log.Fatalln("❌ Reindex failed:", err)
+		}
+		log.Println("✅ Reindex completed.")
+	}
+
+	db.SetSnapshotInterval(snapshotInterval)
+
+	// Maintenance subcommands work on the database without starting the node
+	if args := flag.Args(); len(args) > 0 {
+		if err := runCommand(db, args); err != nil {
+			db.Close()
+			log.Fatalln("❌", err)
+		}
+		return
+	}
+
+	if p2p.AllowUnsigned {
+		log.Println("⚠️ ALLOW_UNSIGNED_VOTES is set: unsigned proposals and votes are accepted and commits aren't checked for a quorum")
+	} else if len(p2p.Validators) == 0 {
+		db.Close()
+		log.Fatalln("❌ Set VALIDATORS to the validator addresses, or ALLOW_UNSIGNED_VOTES=true to accept unsigned proposals and votes")
+	}
+
+	if pruneKeep > 0 {
+		log.Printf("✂️ Pruning mode: keeping the last %d blocks, checking every %s", pruneKeep, pruneInterval)
+		db.StartPruning(pruneKeep, pruneInterval)
+	}
+
 	if _, err := db.GetLatestBlock(); err != nil {
 		log.Println("📦 No blocks found. Creating genesis block...")
 		genesis := blockchain.CreateGenesisBlock()
@@ -53,6 +182,12 @@ func main() {
 	log.Println("🔄 This node is Syncing...")
 	state := p2p.StateSyncing
 	if len(peers) > 0 {
+		// A new node can skip replaying the chain by starting from a peer's state snapshot
+		if latest, err := db.GetLatestBlock(); fastSync && err == nil && latest.Height == 0 {
+			if err := p2p.FastSyncFromPeer(peers[0], db, checkpoint); err != nil {
+				log.Println("⚠️ Fast sync failed, falling back to full sync:", err)
+			}
+		}
 		p2p.SyncFromPeerByHeight(peers[0], db)
 		log.Println("🎉 Sync completed successfully.")
 	} else {
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"golang-chain/pkg/p2p/pb"
+	"golang-chain/pkg/wallet"
+
+	"google.golang.org/grpc"
+)
+
+// The signer holds the private keys of wallets in a separate process and signs
+// hashes for the node and the CLI tools, which only get the signatures. Point them
+// at it with SIGNER_ADDR, e.g. SIGNER_ADDR=unix:///run/chain/signer.sock.
+func main() {
+	socket := flag.String("socket", "signer.sock", "Unix socket to listen on, only accessible by its owner")
+	listen := flag.String("listen", "", "TCP address to listen on instead of the socket, e.g. 127.0.0.1:50100")
+	names := flag.String("keys", "", "Comma-separated wallets whose keys are served")
+	flag.Parse()
+
+	if *names == "" {
+		log.Fatalln("⚠️  Usage: ./signer --keys Alice,validator1 [--socket signer.sock | --listen 127.0.0.1:50100]")
+	}
+
+	// Wallets are decrypted once at startup, with WALLET_PASSPHRASE or a prompt each
+	signers := make(map[string]wallet.Signer)
+	for _, name := range strings.Split(*names, ",") {
+		name = strings.TrimSpace(name)
+		w, err := wallet.LoadWallet(name)
+		if err != nil {
+			log.Fatalf("❌ Failed to load wallet %s: %v", name, err)
+		}
+		signers[name] = w.Signer()
+		log.Printf("🔑 Serving %s (%s)", name, wallet.DisplayAddress(w.PublicKey.Address()))
+	}
+
+	var lis net.Listener
+	var err error
+	if *listen != "" {
+		log.Println("⚠️  Requests over TCP aren't encrypted or authenticated, only listen on a private network")
+		lis, err = net.Listen("tcp", *listen)
+	} else {
+		// A socket left by a signer that didn't stop cleanly is replaced
+		os.Remove(*socket)
+		if lis, err = net.Listen("unix", *socket); err == nil {
+			err = os.Chmod(*socket, 0o600)
+		}
+	}
+	if err != nil {
+		log.Fatalln("❌ Failed to listen:", err)
+	}
+
+	server := grpc.NewServer()
+	pb.RegisterSignerServiceServer(server, wallet.NewSignerServer(signers))
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		log.Println("👋 Stopping signer")
+		server.GracefulStop()
+	}()
+
+	log.Println("✍️  Signer listening on", lis.Addr())
+	if err := server.Serve(lis); err != nil {
+		log.Fatalln("❌ Signer stopped:", err)
+	}
+}
@@ -0,0 +1,214 @@
+package archive_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang-chain/pkg/archive"
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/keys"
+	"golang-chain/pkg/state"
+	"golang-chain/pkg/storage"
+)
+
+// emptyChain returns the genesis block followed by n blocks without transactions.
+// The chain doesn't issue coins, and an import replays the chain from an empty state,
+// so an archive can't hold transfers the accounts weren't funded for.
+func emptyChain(t *testing.T, n int) []*blockchain.Block {
+	t.Helper()
+	root, err := state.New(nil).Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	blocks := []*blockchain.Block{blockchain.CreateGenesisBlock()}
+	for i := 1; i <= n; i++ {
+		prev := blocks[len(blocks)-1]
+		blocks = append(blocks, blockchain.NewBlock(nil, prev.CurrentBlockHash, prev.Height+1, root))
+	}
+	return blocks
+}
+
+func newDB(t *testing.T, blocks []*blockchain.Block) *storage.DB {
+	t.Helper()
+	db := storage.NewDBWithStore(storage.NewMemoryStore())
+	for _, b := range blocks {
+		if err := db.SaveBlock(b); err != nil {
+			t.Fatalf("SaveBlock(%d): %v", b.Height, err)
+		}
+	}
+	return db
+}
+
+// exportFile exports db to a file in a temporary directory and returns its path
+func exportFile(t *testing.T, db *storage.DB) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "chain.archive")
+	if _, err := archive.ExportFile(db, path); err != nil {
+		t.Fatalf("ExportFile: %v", err)
+	}
+	return path
+}
+
+// checkChain fails unless db holds exactly blocks
+func checkChain(t *testing.T, db *storage.DB, blocks []*blockchain.Block) {
+	t.Helper()
+	latest, err := db.GetLatestBlock()
+	if err != nil || latest.Height != blocks[len(blocks)-1].Height {
+		t.Fatalf("latest block: got %+v, %v", latest, err)
+	}
+	for _, want := range blocks {
+		got, err := db.GetBlockByHeight(want.Height)
+		if err != nil || got.CurrentBlockHash != want.CurrentBlockHash {
+			t.Fatalf("block at %d: got %v, want %s", want.Height, err, want.CurrentBlockHash)
+		}
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	blocks := emptyChain(t, 5)
+	path := exportFile(t, newDB(t, blocks))
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	n, err := archive.Verify(f)
+	f.Close()
+	if err != nil || n != 6 {
+		t.Fatalf("Verify: got %d, %v", n, err)
+	}
+
+	db := newDB(t, nil)
+	if n, err := archive.ImportFile(db, path); err != nil || n != 6 {
+		t.Fatalf("ImportFile: got %d, %v", n, err)
+	}
+	checkChain(t, db, blocks)
+	if err := db.CheckConsistency(); err != nil {
+		t.Errorf("CheckConsistency after import: %v", err)
+	}
+
+	// Importing again skips the blocks already held
+	if _, err := archive.ImportFile(db, path); err != nil {
+		t.Fatalf("second ImportFile: %v", err)
+	}
+	checkChain(t, db, blocks)
+}
+
+func TestImportRejectsCorruptArchive(t *testing.T) {
+	blocks := emptyChain(t, 3)
+	path := exportFile(t, newDB(t, blocks))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	// A changed hash still decodes as a block, only the checksum gives it away
+	hash := []byte(blocks[2].CurrentBlockHash)
+	i := bytes.Index(data, hash)
+	if i < 0 {
+		t.Fatalf("archive doesn't hold the hash of block 2")
+	}
+	changed := append([]byte{}, data...)
+	changed[i] ^= 1
+
+	truncated := data[:len(data)-1]
+
+	checksum := append([]byte{}, data...)
+	checksum[len(checksum)-1] ^= 1
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error // nil for any error
+	}{
+		{"changed block", changed, archive.ErrChecksum},
+		{"damaged checksum", checksum, archive.ErrChecksum},
+		{"truncated file", truncated, nil},
+	}
+	for _, tc := range tests {
+		bad := filepath.Join(t.TempDir(), "bad.archive")
+		if err := os.WriteFile(bad, tc.data, 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		db := newDB(t, nil)
+		if _, err := archive.ImportFile(db, bad); err == nil {
+			t.Errorf("%s: ImportFile accepted it", tc.name)
+			continue
+		} else if tc.err != nil && !errors.Is(err, tc.err) {
+			t.Errorf("%s: want %v, got %v", tc.name, tc.err, err)
+		}
+		// Nothing is written before the whole archive is checked
+		if _, err := db.GetLatestBlock(); err != storage.ErrNotFound {
+			t.Errorf("%s: blocks were written: %v", tc.name, err)
+		}
+	}
+}
+
+func TestImportIntoNonEmptyDB(t *testing.T) {
+	blocks := emptyChain(t, 4)
+	path := exportFile(t, newDB(t, blocks))
+
+	// A node holding the start of the same chain is extended
+	db := newDB(t, blocks[:3])
+	if _, err := archive.ImportFile(db, path); err != nil {
+		t.Fatalf("ImportFile into a shorter chain: %v", err)
+	}
+	checkChain(t, db, blocks)
+
+	// A node holding a different block at some height is left alone
+	store := storage.NewMemoryStore()
+	other := storage.NewDBWithStore(store)
+	sender, err := keys.GenerateKey(keys.Ed25519)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	acc := state.NewAccount()
+	acc.Balance.SetFloat64(10)
+	data, err := acc.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := store.Put([]byte("s/"+sender.Public().Address()), data); err != nil {
+		t.Fatalf("Put(sender account): %v", err)
+	}
+	st := state.New(nil)
+	st.Set(sender.Public().Address(), acc)
+
+	tx := blockchain.NewTransaction(sender.Public(), []byte("bob"), 1)
+	if err := tx.Sign(sender); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := st.ApplyTx(tx); err != nil {
+		t.Fatalf("ApplyTx: %v", err)
+	}
+	root, err := st.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	fork := blockchain.NewBlock([]*blockchain.Transaction{tx}, blocks[0].CurrentBlockHash, 1, root)
+	for _, b := range []*blockchain.Block{blocks[0], fork} {
+		if err := other.SaveBlock(b); err != nil {
+			t.Fatalf("SaveBlock(%d): %v", b.Height, err)
+		}
+	}
+
+	if _, err := archive.ImportFile(other, path); err == nil {
+		t.Fatalf("ImportFile over a different chain succeeded")
+	}
+	checkChain(t, other, []*blockchain.Block{blocks[0], fork})
+	if bal, _ := other.GetBalance("bob"); bal.Text('f', 2) != "1.00" {
+		t.Errorf("balance of bob after the refused import: %s", bal.Text('f', 2))
+	}
+
+	// A pruned node no longer holds the old blocks an archive starts with
+	pruned := newDB(t, blocks)
+	if _, err := pruned.Prune(1); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if _, err := archive.Export(pruned, new(bytes.Buffer)); err == nil {
+		t.Errorf("Export of a pruned node succeeded")
+	}
+}
@@ -0,0 +1,200 @@
+// Package archive reads and writes chain archives: portable files holding a whole chain,
+// used to back up a node and to seed a new one without a running network.
+//
+// An archive is laid out as:
+//
+//	magic      8 bytes, "GCARCHV1"
+//	records    per block: 4-byte big-endian length, then the block as JSON,
+//	           in height order starting with the genesis block
+//	end        4 zero bytes (a zero-length record)
+//	count      8-byte big-endian number of blocks
+//	checksum   SHA-256 of everything before it
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang-chain/pkg/blockchain"
+)
+
+var magic = []byte("GCARCHV1")
+
+// maxRecordSize guards against allocating absurd amounts of memory for a corrupt length
+const maxRecordSize = 64 << 20
+
+// ErrChecksum is returned when an archive doesn't match its checksum
+var ErrChecksum = errors.New("archive checksum mismatch")
+
+// Writer writes blocks to an archive. Close must be called to write the trailer.
+type Writer struct {
+	w     *bufio.Writer
+	hash  hash.Hash
+	count uint64
+}
+
+// NewWriter writes the archive header to w and returns a Writer for the blocks
+func NewWriter(w io.Writer) (*Writer, error) {
+	aw := &Writer{w: bufio.NewWriter(w), hash: sha256.New()}
+	if err := aw.write(magic); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// write sends p to the output and the running checksum
+func (aw *Writer) write(p []byte) error {
+	aw.hash.Write(p)
+	_, err := aw.w.Write(p)
+	return err
+}
+
+// WriteBlock appends one block
+func (aw *Writer) WriteBlock(block *blockchain.Block) error {
+	data, err := json.Marshal(block)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 || len(data) > maxRecordSize {
+		return fmt.Errorf("block %d encodes to %d bytes", block.Height, len(data))
+	}
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(data)))
+	if err := aw.write(size[:]); err != nil {
+		return err
+	}
+	if err := aw.write(data); err != nil {
+		return err
+	}
+	aw.count++
+	return nil
+}
+
+// Close writes the end marker, block count and checksum and flushes the output.
+// It doesn't close the underlying writer.
+func (aw *Writer) Close() error {
+	var trailer [12]byte
+	binary.BigEndian.PutUint64(trailer[4:], aw.count)
+	if err := aw.write(trailer[:]); err != nil {
+		return err
+	}
+	if _, err := aw.w.Write(aw.hash.Sum(nil)); err != nil {
+		return err
+	}
+	return aw.w.Flush()
+}
+
+// Reader reads blocks from an archive
+type Reader struct {
+	r     *bufio.Reader
+	hash  hash.Hash
+	count uint64
+	done  bool
+}
+
+// NewReader checks the archive header and returns a Reader for the blocks
+func NewReader(r io.Reader) (*Reader, error) {
+	ar := &Reader{r: bufio.NewReader(r), hash: sha256.New()}
+	head := make([]byte, len(magic))
+	if err := ar.read(head); err != nil {
+		return nil, fmt.Errorf("reading archive header: %w", err)
+	}
+	if !bytes.Equal(head, magic) {
+		return nil, errors.New("not a chain archive")
+	}
+	return ar, nil
+}
+
+// read fills p from the input and adds it to the running checksum
+func (ar *Reader) read(p []byte) error {
+	if _, err := io.ReadFull(ar.r, p); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	ar.hash.Write(p)
+	return nil
+}
+
+// Next returns the next block. After the last block it checks the trailer
+// and returns io.EOF, or ErrChecksum if the archive was damaged.
+// Blocks are returned before the checksum can be checked, so callers
+// that must not act on a damaged archive should run Verify first.
+func (ar *Reader) Next() (*blockchain.Block, error) {
+	if ar.done {
+		return nil, io.EOF
+	}
+
+	var size [4]byte
+	if err := ar.read(size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n == 0 {
+		return nil, ar.finish()
+	}
+	if n > maxRecordSize {
+		return nil, fmt.Errorf("record of %d bytes is too large", n)
+	}
+
+	data := make([]byte, n)
+	if err := ar.read(data); err != nil {
+		return nil, err
+	}
+	var block blockchain.Block
+	if err := json.Unmarshal(data, &block); err != nil {
+		return nil, fmt.Errorf("record %d: %w", ar.count, err)
+	}
+	ar.count++
+	return &block, nil
+}
+
+// finish checks the block count and checksum after the end marker
+func (ar *Reader) finish() error {
+	ar.done = true
+
+	var count [8]byte
+	if err := ar.read(count[:]); err != nil {
+		return err
+	}
+	if binary.BigEndian.Uint64(count[:]) != ar.count {
+		return fmt.Errorf("archive says %d blocks, found %d", binary.BigEndian.Uint64(count[:]), ar.count)
+	}
+
+	expected := ar.hash.Sum(nil)
+	sum := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(ar.r, sum); err != nil {
+		return ErrChecksum
+	}
+	if !bytes.Equal(sum, expected) {
+		return ErrChecksum
+	}
+	if _, err := ar.r.ReadByte(); err != io.EOF {
+		return errors.New("trailing data after archive checksum")
+	}
+	return io.EOF
+}
+
+// Verify reads a whole archive and checks its structure and checksum.
+// It returns the number of blocks in it.
+func Verify(r io.Reader) (uint64, error) {
+	ar, err := NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if _, err := ar.Next(); err == io.EOF {
+			return ar.count, nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+}
@@ -6,56 +6,76 @@ import (
 	"encoding/json"
 )
 
+// Block format versions. Blocks created before versioning carry no Version
+// field (zero) and follow the version 1 rules.
+const (
+	// BlockVersion1 blocks use the original Merkle tree, which duplicates the last node
+	// on odd levels and hashes leaves and inner nodes the same way, and hash the whole block.
+	BlockVersion1 int32 = 1
+	// BlockVersion2 blocks use the RFC 6962 Merkle tree and hash only the header,
+	// which then commits to the transactions through the Merkle root.
+	BlockVersion2 int32 = 2
+
+	// CurrentBlockVersion is the version new blocks are created with
+	CurrentBlockVersion = BlockVersion2
+)
+
 type Block struct {
 	Transactions     []*Transaction
 	MerkleRoot       string
 	PrevBlockHash    string
 	CurrentBlockHash string
 	Height           int64
+	// StateRoot commits to all account state after the block is applied.
+	// It's omitted when empty so blocks created before state roots keep their hash.
+	StateRoot string `json:",omitempty"`
+	// Version selects the block format rules, see BlockVersion1 and BlockVersion2.
+	Version int32 `json:",omitempty"`
 }
 
-// CalculateMerkleRoot computes the Merkle root from all transactions in the block.
-func CalculateMerkleRoot(txs []*Transaction) string {
-	var txHashes [][]byte
-	for _, tx := range txs {
-		hash, err := tx.Hash()
-		if err != nil {
-			panic(err)
-		}
-		txHashes = append(txHashes, hash)
-	}
-	return hex.EncodeToString(buildMerkleRoot(txHashes))
+// BlockHeader holds everything in a block except its transactions.
+// Headers and transaction lists are stored separately.
+type BlockHeader struct {
+	MerkleRoot       string
+	PrevBlockHash    string
+	CurrentBlockHash string
+	Height           int64
+	StateRoot        string `json:",omitempty"`
+	Version          int32  `json:",omitempty"`
 }
 
-// buildMerkleRoot recursively builds the Merkle tree and returns the root hash.
-// If there's an odd number of nodes, the last one is duplicated to balance the tree.
-func buildMerkleRoot(leaves [][]byte) []byte {
-	if len(leaves) == 0 {
-		return []byte{}
-	}
-	if len(leaves) == 1 {
-		return leaves[0]
+// Header returns the header of the block.
+func (b *Block) Header() *BlockHeader {
+	return &BlockHeader{
+		MerkleRoot:       b.MerkleRoot,
+		PrevBlockHash:    b.PrevBlockHash,
+		CurrentBlockHash: b.CurrentBlockHash,
+		Height:           b.Height,
+		StateRoot:        b.StateRoot,
+		Version:          b.Version,
 	}
+}
 
-	var newLevel [][]byte
-	for i := 0; i < len(leaves); i += 2 {
-		left := leaves[i]
-		var right []byte
-		if i+1 < len(leaves) {
-			right = leaves[i+1]
-		} else {
-			right = left
-		}
-		hash := sha256.Sum256(append(left, right...))
-		newLevel = append(newLevel, hash[:])
+// AssembleBlock rebuilds a block from its header and transactions.
+func AssembleBlock(header *BlockHeader, txs []*Transaction) *Block {
+	return &Block{
+		Transactions:     txs,
+		MerkleRoot:       header.MerkleRoot,
+		PrevBlockHash:    header.PrevBlockHash,
+		CurrentBlockHash: header.CurrentBlockHash,
+		Height:           header.Height,
+		StateRoot:        header.StateRoot,
+		Version:          header.Version,
 	}
-
-	return buildMerkleRoot(newLevel)
 }
 
 // HashBlock computes a SHA-256 hash of the block's contents,
 // excluding its own current hash to avoid circular dependency.
+// From version 2 on only the header is hashed, see HashHeader.
 func HashBlock(b *Block) string {
+	if b.Version >= BlockVersion2 {
+		return HashHeader(b.Header())
+	}
 	copyBlock := *b
 	copyBlock.CurrentBlockHash = ""
 	data, _ := json.Marshal(copyBlock)
@@ -63,14 +83,27 @@ func HashBlock(b *Block) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// HashHeader computes the hash of a version 2 block from its header alone,
+// so a header can be checked without the transactions.
+func HashHeader(h *BlockHeader) string {
+	copyHeader := *h
+	copyHeader.CurrentBlockHash = ""
+	data, _ := json.Marshal(copyHeader)
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
 // NewBlock creates a new block with the given transactions,
 // links it to the previous block via PrevBlockHash, and calculates the Merkle root and hash.
-func NewBlock(txs []*Transaction, prevHash string, height int64) *Block {
+// stateRoot is the root of the account state after applying the transactions.
+func NewBlock(txs []*Transaction, prevHash string, height int64, stateRoot string) *Block {
 	block := &Block{
 		Transactions:  txs,
 		PrevBlockHash: prevHash,
-		MerkleRoot:    CalculateMerkleRoot(txs),
+		MerkleRoot:    CalculateMerkleRoot(txs, CurrentBlockVersion),
 		Height:        height,
+		StateRoot:     stateRoot,
+		Version:       CurrentBlockVersion,
 	}
 	block.CurrentBlockHash = HashBlock(block)
 	return block
@@ -78,6 +111,7 @@ func NewBlock(txs []*Transaction, prevHash string, height int64) *Block {
 
 // CreateGenesisBlock initializes the first block of the blockchain.
 // It has no transactions and no previous hash, and is always at height 0.
+// It stays a version 1 block so that every node keeps agreeing on its hash.
 func CreateGenesisBlock() *Block {
 	block := &Block{
 		Height:        0,
@@ -1,29 +1,92 @@
 package blockchain
 
-import "sync"
+import (
+	"bytes"
+	"errors"
+	"sync"
+)
+
+// ErrTxPending is returned by AddPendingTx for a transaction that is already in the pool
+var ErrTxPending = errors.New("transaction already pending")
 
 // PendingTxs stores transactions that haven't been included in a block yet.
 // These will be processed by the leader node during the next block creation cycle.
 var (
 	PendingTxs   []*Transaction // Slice holding pending transactions
+	inFlightTxs  []*Transaction // Taken by the leader for a block that isn't stored yet
 	pendingMutex sync.Mutex     // Mutex to ensure thread-safe access to PendingTxs
 )
 
 // AddPendingTx safely adds a transaction to the pending pool.
-func AddPendingTx(tx *Transaction) {
+// A transaction whose hash is already pending or in flight is refused with ErrTxPending,
+// validators reject blocks holding the same transaction twice.
+func AddPendingTx(tx *Transaction) error {
+	return AddPendingTxIf(tx, nil)
+}
+
+// AddPendingTxIf adds a transaction to the pending pool like AddPendingTx, if check passes.
+// check runs under the pool lock with the pending and in-flight transactions, so nothing
+// is added to the pool between the check and the add.
+func AddPendingTxIf(tx *Transaction, check func(queued []*Transaction) error) error {
 	pendingMutex.Lock()
 	defer pendingMutex.Unlock()
+
+	hash, err := tx.Hash()
+	if err != nil {
+		return err
+	}
+	queued := queuedTxs()
+	for _, q := range queued {
+		if h, err := q.Hash(); err == nil && bytes.Equal(h, hash) {
+			return ErrTxPending
+		}
+	}
+	if check != nil {
+		if err := check(queued); err != nil {
+			return err
+		}
+	}
 	PendingTxs = append(PendingTxs, tx)
+	return nil
+}
+
+// ViewPendingTxs calls fn under the pool lock with the pending and in-flight transactions
+func ViewPendingTxs(fn func(queued []*Transaction) error) error {
+	pendingMutex.Lock()
+	defer pendingMutex.Unlock()
+
+	return fn(queuedTxs())
+}
+
+// queuedTxs returns the in-flight transactions followed by the pending ones, in the
+// order they are applied. The caller must hold pendingMutex.
+func queuedTxs() []*Transaction {
+	return append(append([]*Transaction(nil), inFlightTxs...), PendingTxs...)
 }
 
 // GetAndClearPendingTxs retrieves all pending transactions and clears the pool.
 // This is called by the leader when it's ready to create a new block.
 // The returned list is used to construct the block's transaction set.
+// The transactions stay in flight until ClearInFlightTxs is called.
 func GetAndClearPendingTxs() []*Transaction {
 	pendingMutex.Lock()
 	defer pendingMutex.Unlock()
 
 	txs := PendingTxs
 	PendingTxs = nil
+	inFlightTxs = append(inFlightTxs, txs...)
 	return txs
 }
+
+// ClearInFlightTxs forgets the transactions taken by GetAndClearPendingTxs.
+// save, if not nil, stores the block that includes them first, under the pool lock,
+// so that checks never see the transactions both stored and in flight, or neither.
+func ClearInFlightTxs(save func()) {
+	pendingMutex.Lock()
+	defer pendingMutex.Unlock()
+
+	if save != nil {
+		save()
+	}
+	inFlightTxs = nil
+}
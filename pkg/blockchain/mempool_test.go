@@ -0,0 +1,90 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+)
+
+// resetPool empties the pool before and after a test
+func resetPool(t *testing.T) {
+	GetAndClearPendingTxs()
+	ClearInFlightTxs(nil)
+	t.Cleanup(func() {
+		GetAndClearPendingTxs()
+		ClearInFlightTxs(nil)
+	})
+}
+
+func TestAddPendingTxRefusesDuplicate(t *testing.T) {
+	resetPool(t)
+	txs := testTxs(2)
+
+	for _, tx := range txs {
+		if err := AddPendingTx(tx); err != nil {
+			t.Fatalf("AddPendingTx: %v", err)
+		}
+	}
+	// The same transaction submitted again, as a separate copy
+	dup := *txs[0]
+	if err := AddPendingTx(&dup); !errors.Is(err, ErrTxPending) {
+		t.Fatalf("duplicate: want ErrTxPending, got %v", err)
+	}
+	if got := GetAndClearPendingTxs(); len(got) != 2 {
+		t.Fatalf("pool holds %d transactions, want 2", len(got))
+	}
+
+	// Taken by the leader, the transaction is in flight and still can't be added
+	if err := AddPendingTx(&dup); !errors.Is(err, ErrTxPending) {
+		t.Fatalf("duplicate of an in-flight tx: want ErrTxPending, got %v", err)
+	}
+	ClearInFlightTxs(nil)
+	if err := AddPendingTx(&dup); err != nil {
+		t.Fatalf("AddPendingTx after the block was given up: %v", err)
+	}
+}
+
+func TestInFlightTxs(t *testing.T) {
+	resetPool(t)
+	txs := testTxs(3)
+
+	AddPendingTx(txs[0])
+	GetAndClearPendingTxs()
+	AddPendingTx(txs[1])
+
+	// Checks see the in-flight transactions first, then the pending ones
+	queued := func() []*Transaction {
+		var out []*Transaction
+		ViewPendingTxs(func(q []*Transaction) error {
+			out = q
+			return nil
+		})
+		return out
+	}
+	if got := queued(); len(got) != 2 || got[0] != txs[0] || got[1] != txs[1] {
+		t.Fatalf("queued: got %v", got)
+	}
+
+	// A failing check leaves the pool alone
+	refused := errors.New("refused")
+	err := AddPendingTxIf(txs[2], func(q []*Transaction) error {
+		if len(q) != 2 {
+			t.Errorf("check saw %d queued transactions, want 2", len(q))
+		}
+		return refused
+	})
+	if !errors.Is(err, refused) {
+		t.Fatalf("AddPendingTxIf: want the check's error, got %v", err)
+	}
+
+	// Saving the block and forgetting its transactions happen in one step
+	saved := false
+	ClearInFlightTxs(func() {
+		saved = true
+		if got := len(inFlightTxs); got != 1 {
+			t.Errorf("%d transactions in flight while saving, want 1", got)
+		}
+	})
+	if got := queued(); !saved || len(got) != 1 || got[0] != txs[1] {
+		t.Fatalf("after clearing: saved %v, queued %v", saved, got)
+	}
+}
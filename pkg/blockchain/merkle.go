@@ -0,0 +1,96 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Domain separation prefixes of the RFC 6962 Merkle tree.
+// They keep a leaf from ever being mistaken for an inner node and the other way round.
+const (
+	leafPrefix  = 0x00
+	innerPrefix = 0x01
+)
+
+// CalculateMerkleRoot computes the Merkle root from all transactions in the block,
+// using the tree construction of the given block version.
+func CalculateMerkleRoot(txs []*Transaction, version int32) string {
+	var txHashes [][]byte
+	for _, tx := range txs {
+		hash, err := tx.Hash()
+		if err != nil {
+			panic(err)
+		}
+		txHashes = append(txHashes, hash)
+	}
+	if version >= BlockVersion2 {
+		return hex.EncodeToString(rfc6962Root(txHashes))
+	}
+	return hex.EncodeToString(buildMerkleRoot(txHashes))
+}
+
+// buildMerkleRoot recursively builds the version 1 Merkle tree and returns the root hash.
+// If there's an odd number of nodes, the last one is duplicated to balance the tree.
+// This makes [a b c] and [a b c c] share a root (the CVE-2012-2459 mutation),
+// which is why version 2 blocks use rfc6962Root instead.
+func buildMerkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return []byte{}
+	}
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+
+	var newLevel [][]byte
+	for i := 0; i < len(leaves); i += 2 {
+		left := leaves[i]
+		var right []byte
+		if i+1 < len(leaves) {
+			right = leaves[i+1]
+		} else {
+			right = left
+		}
+		hash := sha256.Sum256(append(left, right...))
+		newLevel = append(newLevel, hash[:])
+	}
+
+	return buildMerkleRoot(newLevel)
+}
+
+// rfc6962Root computes the Merkle tree hash of RFC 6962 section 2.1: leaves and inner nodes
+// are hashed with different prefixes and the tree is split at the largest power of two
+// below its size, so no node is ever duplicated. The empty tree hashes to SHA-256("").
+func rfc6962Root(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		hash := sha256.Sum256(nil)
+		return hash[:]
+	case 1:
+		return leafHash(leaves[0])
+	}
+	k := splitPoint(len(leaves))
+	return innerHash(rfc6962Root(leaves[:k]), rfc6962Root(leaves[k:]))
+}
+
+func leafHash(data []byte) []byte {
+	hash := sha256.Sum256(append([]byte{leafPrefix}, data...))
+	return hash[:]
+}
+
+func innerHash(left, right []byte) []byte {
+	buf := make([]byte, 0, 1+len(left)+len(right))
+	buf = append(buf, innerPrefix)
+	buf = append(buf, left...)
+	buf = append(buf, right...)
+	hash := sha256.Sum256(buf)
+	return hash[:]
+}
+
+// splitPoint returns the largest power of two smaller than n, for n > 1
+func splitPoint(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
@@ -0,0 +1,167 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// MerkleProof shows that a transaction is part of a block's Merkle tree.
+// Siblings are the hex-encoded hashes met on the way from the leaf up to the root.
+// Which side of the path each of them sits on follows from Index, and for
+// version 2 trees also from TreeSize, the number of transactions in the block.
+type MerkleProof struct {
+	TxHash   string
+	Index    int
+	TreeSize int
+	Siblings []string
+}
+
+// BuildMerkleProof creates the inclusion proof of the transaction at index in txs,
+// for the Merkle tree of the given block version.
+func BuildMerkleProof(txs []*Transaction, index int, version int32) (*MerkleProof, error) {
+	if index < 0 || index >= len(txs) {
+		return nil, fmt.Errorf("transaction index %d out of range", index)
+	}
+
+	var leaves [][]byte
+	for _, tx := range txs {
+		hash, err := tx.Hash()
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, hash)
+	}
+
+	proof := &MerkleProof{
+		TxHash:   hex.EncodeToString(leaves[index]),
+		Index:    index,
+		TreeSize: len(leaves),
+	}
+
+	var path [][]byte
+	if version >= BlockVersion2 {
+		path = rfc6962Path(leaves, index)
+	} else {
+		path = legacyPath(leaves, index)
+	}
+	for _, sibling := range path {
+		proof.Siblings = append(proof.Siblings, hex.EncodeToString(sibling))
+	}
+	return proof, nil
+}
+
+// legacyPath walks the same tree as buildMerkleRoot, including the duplicated last node on odd levels.
+func legacyPath(level [][]byte, pos int) [][]byte {
+	var path [][]byte
+	for len(level) > 1 {
+		sibling := pos ^ 1
+		if sibling >= len(level) {
+			sibling = pos
+		}
+		path = append(path, level[sibling])
+
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			hash := sha256.Sum256(append(append([]byte{}, level[i]...), right...))
+			next = append(next, hash[:])
+		}
+		level = next
+		pos /= 2
+	}
+	return path
+}
+
+// rfc6962Path returns the audit path of RFC 6962 section 2.1.1, leaf side first.
+func rfc6962Path(leaves [][]byte, index int) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := splitPoint(len(leaves))
+	if index < k {
+		return append(rfc6962Path(leaves[:k], index), rfc6962Root(leaves[k:]))
+	}
+	return append(rfc6962Path(leaves[k:], index-k), rfc6962Root(leaves[:k]))
+}
+
+// VerifyMerkleProof recomputes the root from the proof and compares it with merkleRoot.
+// version is the version of the block the root comes from; take it from a header you trust,
+// not from whoever handed out the proof.
+func VerifyMerkleProof(proof *MerkleProof, merkleRoot string, version int32) (bool, error) {
+	if proof.Index < 0 {
+		return false, errors.New("negative transaction index")
+	}
+	leaf, err := hex.DecodeString(proof.TxHash)
+	if err != nil {
+		return false, fmt.Errorf("invalid tx hash: %w", err)
+	}
+	var siblings [][]byte
+	for _, s := range proof.Siblings {
+		sibling, err := hex.DecodeString(s)
+		if err != nil {
+			return false, fmt.Errorf("invalid sibling hash: %w", err)
+		}
+		siblings = append(siblings, sibling)
+	}
+	root, err := hex.DecodeString(merkleRoot)
+	if err != nil {
+		return false, fmt.Errorf("invalid Merkle root: %w", err)
+	}
+
+	if version >= BlockVersion2 {
+		return verifyRFC6962Path(leaf, proof.Index, proof.TreeSize, siblings, root), nil
+	}
+	return verifyLegacyPath(leaf, proof.Index, siblings, root), nil
+}
+
+func verifyLegacyPath(node []byte, pos int, siblings [][]byte, root []byte) bool {
+	for _, sibling := range siblings {
+		var hash [32]byte
+		if pos%2 == 0 {
+			hash = sha256.Sum256(append(append([]byte{}, node...), sibling...))
+		} else {
+			hash = sha256.Sum256(append(append([]byte{}, sibling...), node...))
+		}
+		node = hash[:]
+		pos /= 2
+	}
+
+	// Leftover index bits mean the proof is too short for the claimed position
+	if pos != 0 {
+		return false
+	}
+	return bytes.Equal(node, root)
+}
+
+// verifyRFC6962Path checks an audit path following RFC 9162 section 2.1.3.2.
+func verifyRFC6962Path(leaf []byte, index, size int, siblings [][]byte, root []byte) bool {
+	if index >= size {
+		return false
+	}
+	fn, sn := index, size-1
+	node := leafHash(leaf)
+	for _, sibling := range siblings {
+		if sn == 0 {
+			return false
+		}
+		if fn%2 == 1 || fn == sn {
+			node = innerHash(sibling, node)
+			// A right-most node without a sibling on this level is carried up as is
+			for fn%2 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			node = innerHash(node, sibling)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(node, root)
+}
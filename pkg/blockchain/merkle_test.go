@@ -0,0 +1,177 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// testTxs returns n distinct transactions
+func testTxs(n int) []*Transaction {
+	var txs []*Transaction
+	for i := 0; i < n; i++ {
+		txs = append(txs, &Transaction{
+			Sender:    []byte{byte(i)},
+			Receiver:  []byte("receiver"),
+			Amount:    float64(i + 1),
+			Timestamp: 1700000000,
+		})
+	}
+	return txs
+}
+
+// txLeaves returns the tx hashes the Merkle tree is built over
+func txLeaves(t *testing.T, txs []*Transaction) [][]byte {
+	t.Helper()
+	var leaves [][]byte
+	for _, tx := range txs {
+		hash, err := tx.Hash()
+		if err != nil {
+			t.Fatalf("Hash: %v", err)
+		}
+		leaves = append(leaves, hash)
+	}
+	return leaves
+}
+
+// lh and nh spell out the RFC 6962 leaf and node hashes independently of merkle.go
+func lh(data []byte) []byte {
+	hash := sha256.Sum256(append([]byte{0x00}, data...))
+	return hash[:]
+}
+
+func nh(left, right []byte) []byte {
+	hash := sha256.Sum256(append(append([]byte{0x01}, left...), right...))
+	return hash[:]
+}
+
+func TestRFC6962Root(t *testing.T) {
+	txs := testTxs(8)
+	l := txLeaves(t, txs)
+	empty := sha256.Sum256(nil)
+
+	tests := []struct {
+		n    int
+		want []byte
+	}{
+		{0, empty[:]},
+		{1, lh(l[0])},
+		{2, nh(lh(l[0]), lh(l[1]))},
+		{3, nh(nh(lh(l[0]), lh(l[1])), lh(l[2]))},
+		{5, nh(
+			nh(nh(lh(l[0]), lh(l[1])), nh(lh(l[2]), lh(l[3]))),
+			lh(l[4]))},
+		{8, nh(
+			nh(nh(lh(l[0]), lh(l[1])), nh(lh(l[2]), lh(l[3]))),
+			nh(nh(lh(l[4]), lh(l[5])), nh(lh(l[6]), lh(l[7]))))},
+	}
+	for _, tc := range tests {
+		got := CalculateMerkleRoot(txs[:tc.n], BlockVersion2)
+		if want := hex.EncodeToString(tc.want); got != want {
+			t.Errorf("%d leaves: want %s, got %s", tc.n, want, got)
+		}
+	}
+}
+
+func TestLegacyMerkleRoot(t *testing.T) {
+	txs := testTxs(3)
+	l := txLeaves(t, txs)
+	h := func(left, right []byte) []byte {
+		hash := sha256.Sum256(append(append([]byte{}, left...), right...))
+		return hash[:]
+	}
+
+	// Version 1 duplicates the last node on odd levels and doesn't prefix anything
+	want := hex.EncodeToString(h(h(l[0], l[1]), h(l[2], l[2])))
+	for _, version := range []int32{0, BlockVersion1} {
+		if got := CalculateMerkleRoot(txs, version); got != want {
+			t.Errorf("version %d: want legacy root %s, got %s", version, want, got)
+		}
+	}
+	if got := CalculateMerkleRoot(txs[:1], BlockVersion1); got != hex.EncodeToString(l[0]) {
+		t.Errorf("version 1 root of one tx: want the tx hash, got %s", got)
+	}
+
+	// The duplicated last transaction keeps the version 1 root, but not the version 2 one
+	mutated := append(testTxs(3), txs[2])
+	if CalculateMerkleRoot(mutated, BlockVersion1) != want {
+		t.Errorf("version 1 root should be unchanged by duplicating the last tx")
+	}
+	if CalculateMerkleRoot(mutated, BlockVersion2) == CalculateMerkleRoot(txs, BlockVersion2) {
+		t.Errorf("version 2 root must change when the last tx is duplicated")
+	}
+}
+
+func TestMerkleProof(t *testing.T) {
+	for _, version := range []int32{BlockVersion1, BlockVersion2} {
+		for _, n := range []int{1, 2, 3, 5, 8} {
+			t.Run(fmt.Sprintf("v%d/%d", version, n), func(t *testing.T) {
+				txs := testTxs(n)
+				root := CalculateMerkleRoot(txs, version)
+
+				for i := 0; i < n; i++ {
+					proof, err := BuildMerkleProof(txs, i, version)
+					if err != nil {
+						t.Fatalf("BuildMerkleProof(%d): %v", i, err)
+					}
+					if ok, err := VerifyMerkleProof(proof, root, version); err != nil || !ok {
+						t.Fatalf("index %d: valid proof rejected: %v, %v", i, ok, err)
+					}
+
+					if n > 1 {
+						wrong := *proof
+						wrong.Index = (i + 1) % n
+						if ok, _ := VerifyMerkleProof(&wrong, root, version); ok {
+							t.Errorf("index %d: proof accepted at index %d", i, wrong.Index)
+						}
+
+						tampered := *proof
+						tampered.Siblings = append([]string{}, proof.Siblings...)
+						tampered.Siblings[0] = flipHex(tampered.Siblings[0])
+						if ok, _ := VerifyMerkleProof(&tampered, root, version); ok {
+							t.Errorf("index %d: proof with a tampered sibling accepted", i)
+						}
+
+						short := *proof
+						short.Siblings = proof.Siblings[:len(proof.Siblings)-1]
+						if ok, _ := VerifyMerkleProof(&short, root, version); ok {
+							t.Errorf("index %d: truncated proof accepted", i)
+						}
+					}
+
+					other := *proof
+					other.TxHash = flipHex(proof.TxHash)
+					if ok, _ := VerifyMerkleProof(&other, root, version); ok {
+						t.Errorf("index %d: proof of another tx accepted", i)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestMerkleProofOutOfRange(t *testing.T) {
+	txs := testTxs(3)
+	if _, err := BuildMerkleProof(txs, 3, BlockVersion2); err == nil {
+		t.Errorf("BuildMerkleProof accepted an index past the last tx")
+	}
+
+	proof, _ := BuildMerkleProof(txs, 2, BlockVersion2)
+	root := CalculateMerkleRoot(txs, BlockVersion2)
+	proof.Index = -1
+	if ok, err := VerifyMerkleProof(proof, root, BlockVersion2); ok || err == nil {
+		t.Errorf("negative index: got %v, %v", ok, err)
+	}
+	proof.Index = 3
+	if ok, _ := VerifyMerkleProof(proof, root, BlockVersion2); ok {
+		t.Errorf("index equal to the tree size accepted")
+	}
+}
+
+// flipHex flips the lowest bit of the first byte of a hex-encoded hash
+func flipHex(s string) string {
+	b, _ := hex.DecodeString(s)
+	b[0] ^= 1
+	return hex.EncodeToString(b)
+}
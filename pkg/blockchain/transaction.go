@@ -1,14 +1,29 @@
 package blockchain
 
 import (
-	"crypto/ecdsa"
-	"crypto/rand"
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"math/big"
+	"fmt"
 	"time"
+
+	"golang-chain/pkg/keys"
+)
+
+// Transaction format versions. Transactions created before versioning carry no
+// Version and follow the rules of TxVersion1.
+const (
+	// TxVersion1 transactions carry the sender's public key
+	TxVersion1 int32 = 1
+	// TxVersion2 transactions carry no sender key: it's recovered from the signature,
+	// so their key type must be a scheme with recoverable signatures (secp256k1)
+	TxVersion2 int32 = 2
+	// TxVersion3 transactions carry the sender key, a nonce and a fee. The nonce must
+	// equal the number of version 3 transactions the sender has sent, so a transaction
+	// can't be included twice; the fee is paid by the sender on top of the amount.
+	TxVersion3 int32 = 3
 )
 
 type Transaction struct {
@@ -17,17 +32,53 @@ type Transaction struct {
 	Amount    float64
 	Timestamp int64
 	Signature []byte
+	// KeyType is the signature scheme of Sender, whose raw public key it holds.
+	// For keys.Multisig, Sender is the encoded multisig key and Signature holds the
+	// member signatures. Transactions from before schemes were pluggable have none
+	// (zero), their sender is a PEM-encoded P-256 key.
+	KeyType keys.Scheme `json:",omitempty"`
+	// Version selects the transaction format, see TxVersion1, TxVersion2 and TxVersion3
+	Version int32 `json:",omitempty"`
+	// Nonce and Fee are only used by TxVersion3
+	Nonce uint64  `json:",omitempty"`
+	Fee   float64 `json:",omitempty"`
 }
 
-func NewTransaction(sender, receiver []byte, amount float64) *Transaction {
+func NewTransaction(sender keys.PublicKey, receiver []byte, amount float64) *Transaction {
 	return &Transaction{
-		Sender:    sender,
+		Sender:    sender.Bytes(),
 		Receiver:  receiver,
 		Amount:    amount,
 		Timestamp: time.Now().Unix(),
+		KeyType:   sender.Scheme(),
 	}
 }
 
+// NewRecoverableTransaction creates a TxVersion2 transaction, whose sender is
+// recovered from the signature instead of being stored in it
+func NewRecoverableTransaction(scheme keys.Scheme, receiver []byte, amount float64) (*Transaction, error) {
+	if !keys.Recoverable(scheme) {
+		return nil, fmt.Errorf("%s signatures aren't recoverable", scheme)
+	}
+	return &Transaction{
+		Receiver:  receiver,
+		Amount:    amount,
+		Timestamp: time.Now().Unix(),
+		KeyType:   scheme,
+		Version:   TxVersion2,
+	}, nil
+}
+
+// NewNoncedTransaction creates a TxVersion3 transaction, which is only valid as the
+// sender's transaction number nonce and pays fee on top of amount
+func NewNoncedTransaction(sender keys.PublicKey, receiver []byte, amount, fee float64, nonce uint64) *Transaction {
+	tx := NewTransaction(sender, receiver, amount)
+	tx.Version = TxVersion3
+	tx.Nonce = nonce
+	tx.Fee = fee
+	return tx
+}
+
 // Hash calculates the SHA-256 hash of the transaction data.
 func (t *Transaction) Hash() ([]byte, error) {
 	txMap := map[string]interface{}{
@@ -36,6 +87,17 @@ func (t *Transaction) Hash() ([]byte, error) {
 		"amount":    t.Amount,
 		"timestamp": t.Timestamp,
 	}
+	// Only hashed when set, so transactions from before key types keep their hash
+	if t.KeyType != 0 {
+		txMap["keyType"] = t.KeyType
+	}
+	if t.Version >= TxVersion2 {
+		txMap["version"] = t.Version
+	}
+	if t.Version >= TxVersion3 {
+		txMap["nonce"] = t.Nonce
+		txMap["fee"] = t.Fee
+	}
 
 	jsonData, err := json.Marshal(txMap)
 	if err != nil {
@@ -45,38 +107,174 @@ func (t *Transaction) Hash() ([]byte, error) {
 	return hash[:], nil
 }
 
-// Sign signs the transaction hash using the sender's private key.
-// It uses ECDSA and embeds the resulting signature in the transaction.
-func (t *Transaction) Sign(priv *ecdsa.PrivateKey) error {
+// SenderKey returns the sender's public key: decoded according to the transaction's
+// key type, or for TxVersion2 recovered from the signature
+func (t *Transaction) SenderKey() (keys.PublicKey, error) {
+	if err := t.checkVersion(); err != nil {
+		return nil, err
+	}
+	if t.Version == TxVersion2 {
+		hash, err := t.Hash()
+		if err != nil {
+			return nil, err
+		}
+		pub, err := keys.RecoverPublicKey(t.KeyType, hash, t.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("can't recover sender from signature: %w", err)
+		}
+		return pub, nil
+	}
+	if t.KeyType == 0 {
+		return keys.ParsePEMPublicKey(t.Sender)
+	}
+	return keys.ParsePublicKey(t.KeyType, t.Sender)
+}
+
+// checkVersion rejects versions this node doesn't know, version 2 transactions
+// that carry a sender key or use a scheme without recoverable signatures, and
+// nonces and fees outside version 3, where they wouldn't be signed
+func (t *Transaction) checkVersion() error {
+	switch {
+	case t.Version > TxVersion3:
+		return fmt.Errorf("unsupported transaction version %d", t.Version)
+	case t.Version < TxVersion3 && (t.Nonce != 0 || t.Fee != 0):
+		return errors.New("only version 3 transactions have a nonce and a fee")
+	case t.Version == TxVersion3 && len(t.Sender) == 0:
+		return errors.New("version 3 transactions carry the sender key")
+	case t.Fee < 0:
+		return errors.New("negative transaction fee")
+	case t.Version == TxVersion2 && len(t.Sender) != 0:
+		return errors.New("version 2 transactions carry no sender key")
+	case t.Version == TxVersion2 && !keys.Recoverable(t.KeyType):
+		return fmt.Errorf("version 2 transactions need a recoverable signature scheme, not %s", t.KeyType)
+	}
+	return nil
+}
+
+// Sign signs the transaction hash with the sender's key and embeds the resulting
+// signature in the transaction. The signer may be a private key or a remote signer.
+// For a multisig sender, signer holds the key of one member and its signature is
+// added to those already in the transaction.
+func (t *Transaction) Sign(signer keys.Signer) error {
+	if err := t.checkVersion(); err != nil {
+		return err
+	}
 	hash, err := t.Hash()
 	if err != nil {
 		return err
 	}
-	r, s, err := ecdsa.Sign(rand.Reader, priv, hash)
+
+	if t.Version == TxVersion2 {
+		rs, ok := signer.(keys.RecoverableSigner)
+		if !ok || signer.Public().Scheme() != t.KeyType {
+			return fmt.Errorf("signer can't make recoverable %s signatures", t.KeyType)
+		}
+		sig, err := rs.SignRecoverable(hash)
+		if err != nil {
+			return err
+		}
+		t.Signature = sig
+		return nil
+	}
+
+	if t.KeyType == keys.Multisig {
+		ms, err := t.multisigKey()
+		if err != nil {
+			return err
+		}
+		sig, err := ms.AddSignature(t.Signature, signer, hash)
+		if err != nil {
+			return err
+		}
+		t.Signature = sig
+		return nil
+	}
+
+	pub := signer.Public()
+	if pub.Scheme() != t.KeyType || !bytes.Equal(pub.Bytes(), t.Sender) {
+		return errors.New("signer doesn't hold the sender's key")
+	}
+	sig, err := signer.Sign(hash)
 	if err != nil {
 		return err
 	}
-
-	// Normalize the signature to fixed 64 bytes: 32 bytes for R + 32 bytes for S
-	rBytes := r.FillBytes(make([]byte, 32))
-	sBytes := s.FillBytes(make([]byte, 32))
-	t.Signature = append(rBytes, sBytes...)
+	t.Signature = sig
 	return nil
 }
 
 // Verify checks whether the transaction's signature is valid
 // using the sender's public key. It ensures authenticity and integrity.
-func (t *Transaction) Verify(pub *ecdsa.PublicKey) (bool, error) {
-	if len(t.Signature) != 64 {
-		return false, errors.New("invalid signature length")
+func (t *Transaction) Verify() (bool, error) {
+	if err := t.checkVersion(); err != nil {
+		return false, err
+	}
+	if t.Version == TxVersion2 {
+		// Recovery checks the signature against the key it recovers, so any
+		// valid signature verifies; it just determines who the sender is
+		_, err := t.SenderKey()
+		return err == nil, nil
 	}
 
+	pub, err := t.SenderKey()
+	if err != nil {
+		return false, fmt.Errorf("invalid sender key: %w", err)
+	}
 	hash, err := t.Hash()
 	if err != nil {
 		return false, err
 	}
+	return pub.Verify(hash, t.Signature), nil
+}
+
+// multisigKey returns the sender key of a multisig transaction
+func (t *Transaction) multisigKey() (*keys.MultisigKey, error) {
+	pub, err := t.SenderKey()
+	if err != nil {
+		return nil, err
+	}
+	ms, ok := pub.(*keys.MultisigKey)
+	if !ok {
+		return nil, errors.New("sender isn't a multisig account")
+	}
+	return ms, nil
+}
+
+// Signers returns the members of a multisig sender that signed the transaction
+// so far, and how many signatures it needs
+func (t *Transaction) Signers() (signed []keys.PublicKey, threshold int, err error) {
+	ms, err := t.multisigKey()
+	if err != nil {
+		return nil, 0, err
+	}
+	signed, err = ms.Signers(t.Signature)
+	return signed, ms.Threshold(), err
+}
 
-	r := new(big.Int).SetBytes(t.Signature[:32])
-	s := new(big.Int).SetBytes(t.Signature[32:])
-	return ecdsa.Verify(pub, hash, r, s), nil
+// CombineSignatures adds the member signatures of other copies of a multisig
+// transaction to this one. All copies must be of the same transaction.
+func (t *Transaction) CombineSignatures(others ...*Transaction) error {
+	if _, err := t.multisigKey(); err != nil {
+		return err
+	}
+	hash, err := t.Hash()
+	if err != nil {
+		return err
+	}
+	sigs := [][]byte{t.Signature}
+	for _, o := range others {
+		h, err := o.Hash()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(h, hash) {
+			return fmt.Errorf("can't combine signatures of different transactions %x and %x", hash, h)
+		}
+		sigs = append(sigs, o.Signature)
+	}
+	sig, err := keys.CombineSignatures(sigs...)
+	if err != nil {
+		return err
+	}
+	t.Signature = sig
+	return nil
 }
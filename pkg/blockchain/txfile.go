@@ -0,0 +1,103 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang-chain/pkg/keys"
+)
+
+// TxFileVersion is the version of the transaction file format
+const TxFileVersion = 1
+
+// txFile is the JSON layout of a transaction file, used to pass a transaction
+// between the machines that build, sign and broadcast it. Binary fields are hex,
+// the hash is informational and checked when the file is read. The format is
+// documented in the README.
+type txFile struct {
+	FileVersion int     `json:"fileVersion"`
+	Version     int32   `json:"version,omitempty"`
+	KeyType     string  `json:"keyType,omitempty"`
+	Sender      string  `json:"sender"`
+	Receiver    string  `json:"receiver"`
+	Amount      float64 `json:"amount"`
+	Timestamp   int64   `json:"timestamp"`
+	Nonce       uint64  `json:"nonce,omitempty"`
+	Fee         float64 `json:"fee,omitempty"`
+	Signature   string  `json:"signature,omitempty"`
+	Hash        string  `json:"hash"`
+}
+
+// WriteTxFile saves tx, signed or not, to a transaction file
+func WriteTxFile(path string, tx *Transaction) error {
+	hash, err := tx.Hash()
+	if err != nil {
+		return err
+	}
+	f := txFile{
+		FileVersion: TxFileVersion,
+		Version:     tx.Version,
+		Sender:      hex.EncodeToString(tx.Sender),
+		Receiver:    string(tx.Receiver),
+		Amount:      tx.Amount,
+		Timestamp:   tx.Timestamp,
+		Nonce:       tx.Nonce,
+		Fee:         tx.Fee,
+		Signature:   hex.EncodeToString(tx.Signature),
+		Hash:        hex.EncodeToString(hash),
+	}
+	if tx.KeyType != 0 {
+		f.KeyType = tx.KeyType.String()
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ReadTxFile loads a transaction from a transaction file
+func ReadTxFile(path string) (*Transaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f txFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid transaction file: %w", err)
+	}
+	if f.FileVersion < 1 || f.FileVersion > TxFileVersion {
+		return nil, fmt.Errorf("unsupported transaction file version %d", f.FileVersion)
+	}
+
+	tx := &Transaction{
+		Receiver:  []byte(f.Receiver),
+		Amount:    f.Amount,
+		Timestamp: f.Timestamp,
+		Version:   f.Version,
+		Nonce:     f.Nonce,
+		Fee:       f.Fee,
+	}
+	if f.KeyType != "" {
+		if tx.KeyType, err = keys.ParseScheme(f.KeyType); err != nil {
+			return nil, err
+		}
+	}
+	if tx.Sender, err = hex.DecodeString(f.Sender); err != nil {
+		return nil, fmt.Errorf("invalid sender in transaction file: %w", err)
+	}
+	if tx.Signature, err = hex.DecodeString(f.Signature); err != nil {
+		return nil, fmt.Errorf("invalid signature in transaction file: %w", err)
+	}
+
+	hash, err := tx.Hash()
+	if err != nil {
+		return nil, err
+	}
+	if f.Hash != hex.EncodeToString(hash) {
+		return nil, fmt.Errorf("transaction file hash %s doesn't match its content (%x)", f.Hash, hash)
+	}
+	return tx, nil
+}
@@ -0,0 +1,45 @@
+package consensus
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"golang-chain/pkg/keys"
+)
+
+// Proposals and votes are signed over these hashes rather than the block hash
+// itself, so a signature made for one can't be replayed as the other.
+
+// ProposalHash is the hash a leader signs to propose the block with hash blockHash
+func ProposalHash(blockHash string) []byte {
+	hash := sha256.Sum256([]byte("golang-chain proposal:" + blockHash))
+	return hash[:]
+}
+
+// VoteHash is the hash a validator signs to vote on the block with hash blockHash
+func VoteHash(blockHash, nodeID string, approved bool) []byte {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("golang-chain vote:%s:%s:%t", blockHash, nodeID, approved)))
+	return hash[:]
+}
+
+// VerifyValidator checks that sig is a signature of hash by the validator key of the
+// given scheme, and returns the key's address. When validators isn't empty, the
+// address must be one of them.
+func VerifyValidator(validators map[string]bool, scheme keys.Scheme, rawKey, sig, hash []byte) (string, error) {
+	if scheme == keys.Multisig {
+		return "", errors.New("validator keys can't be multisig")
+	}
+	pub, err := keys.ParsePublicKey(scheme, rawKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid validator key: %w", err)
+	}
+	if !pub.Verify(hash, sig) {
+		return "", errors.New("invalid validator signature")
+	}
+	address := pub.Address()
+	if len(validators) > 0 && !validators[address] {
+		return "", fmt.Errorf("%s isn't a validator", address)
+	}
+	return address, nil
+}
@@ -2,23 +2,48 @@ package consensus
 
 import (
 	"golang-chain/pkg/blockchain"
-	"golang-chain/pkg/wallet"
+	"golang-chain/pkg/state"
 	"log"
 )
 
 // VerifyBlock checks whether a proposed block is valid before accepting it.
 // It performs Merkle root verification, hash validation, previous block linkage, height consistency,
-// and signature checks on all transactions.
-func VerifyBlock(block, prevBlock *blockchain.Block) bool {
-	// 1. Recompute and compare Merkle root to ensure integrity of transactions
-	expectedMerkle := blockchain.CalculateMerkleRoot(block.Transactions)
+// signature checks on all transactions and, when the state after prevBlock is given,
+// re-executes the transactions to compare the resulting state root with the block's.
+// preState holds every account and is not modified.
+func VerifyBlock(block, prevBlock *blockchain.Block, preState *state.State) bool {
+	// 0. Only accept block formats we know, and never go back to an older one
+	if block.Version > blockchain.CurrentBlockVersion {
+		log.Println("❌ Unknown block version:", block.Version)
+		return false
+	}
+	if prevBlock != nil && block.Version < prevBlock.Version {
+		log.Println("❌ Block version downgrade:", block.Version, "after", prevBlock.Version)
+		return false
+	}
+
+	// 1. Recompute and compare Merkle root to ensure integrity of transactions.
+	// A transaction listed twice is rejected, in version 1 blocks it could be
+	// smuggled in without changing the Merkle root.
+	expectedMerkle := blockchain.CalculateMerkleRoot(block.Transactions, block.Version)
 	if block.MerkleRoot != expectedMerkle {
+		log.Println("❌ Merkle root mismatch:", block.MerkleRoot, "vs", expectedMerkle)
 		return false
 	}
+	seen := make(map[string]bool)
+	for _, tx := range block.Transactions {
+		hash, err := tx.Hash()
+		if err != nil || seen[string(hash)] {
+			log.Println("❌ Duplicate transaction in block")
+			return false
+		}
+		seen[string(hash)] = true
+	}
 
 	// 2. Recompute and compare block hash to detect tampering
 	expectedHash := blockchain.HashBlock(block)
 	if block.CurrentBlockHash != expectedHash {
+		log.Println("❌ Block hash mismatch:", block.CurrentBlockHash, "vs", expectedHash)
 		return false
 	}
 
@@ -41,13 +66,30 @@ func VerifyBlock(block, prevBlock *blockchain.Block) bool {
 	}
 
 	// 4. Verify digital signatures of all transactions in the block
-	for _, tx := range block.Transactions {
-		pubKey, err := wallet.DecodePublicKey(tx.Sender)
+	for i, tx := range block.Transactions {
+		// The key type of each transaction selects the scheme its signature is checked with.
+		// A multisig sender needs valid signatures of at least its threshold of members.
+		valid, err := tx.Verify()
 		if err != nil {
+			log.Printf("❌ Tx %d: %v", i, err)
+			return false
+		}
+		if !valid {
+			log.Printf("❌ Tx %d has an invalid signature", i)
+			return false
+		}
+	}
+
+	// 5. Execute the transactions locally and compare the state root
+	if preState != nil && prevBlock != nil {
+		post := preState.Copy()
+		if err := post.ApplyBlock(block); err != nil {
+			log.Println("❌ Failed to apply block:", err)
 			return false
 		}
-		valid, err := tx.Verify(pubKey)
-		if err != nil || !valid {
+		root, err := post.Root()
+		if err != nil || block.StateRoot != root {
+			log.Println("❌ State root mismatch:", block.StateRoot, "vs", root)
 			return false
 		}
 	}
@@ -0,0 +1,62 @@
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+)
+
+type ed25519PublicKey struct {
+	key ed25519.PublicKey
+}
+
+type ed25519PrivateKey struct {
+	key ed25519.PrivateKey
+}
+
+func generateEd25519() (PrivateKey, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &ed25519PrivateKey{key: priv}, nil
+}
+
+func parseEd25519PublicKey(data []byte) (PublicKey, error) {
+	if len(data) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid Ed25519 public key length")
+	}
+	return &ed25519PublicKey{key: append(ed25519.PublicKey(nil), data...)}, nil
+}
+
+func parseEd25519PrivateKey(data []byte) (PrivateKey, error) {
+	if len(data) != ed25519.SeedSize {
+		return nil, errors.New("invalid Ed25519 private key length")
+	}
+	return &ed25519PrivateKey{key: ed25519.NewKeyFromSeed(data)}, nil
+}
+
+func (k *ed25519PublicKey) Scheme() Scheme { return Ed25519 }
+
+func (k *ed25519PublicKey) Bytes() []byte { return append([]byte(nil), k.key...) }
+
+func (k *ed25519PublicKey) Verify(hash, sig []byte) bool {
+	return len(sig) == ed25519.SignatureSize && ed25519.Verify(k.key, hash, sig)
+}
+
+// Address hashes the scheme byte and the key with SHA256
+func (k *ed25519PublicKey) Address() string {
+	return schemeAddress(Ed25519, k.key)
+}
+
+func (k *ed25519PrivateKey) Scheme() Scheme { return Ed25519 }
+
+func (k *ed25519PrivateKey) Public() PublicKey {
+	return &ed25519PublicKey{key: k.key.Public().(ed25519.PublicKey)}
+}
+
+func (k *ed25519PrivateKey) Bytes() []byte { return k.key.Seed() }
+
+func (k *ed25519PrivateKey) Sign(hash []byte) ([]byte, error) {
+	return ed25519.Sign(k.key, hash), nil
+}
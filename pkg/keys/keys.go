@@ -0,0 +1,187 @@
+// Package keys implements the signature schemes transactions can be signed with.
+// Every scheme has a one-byte identifier stored in the transaction, and a compact
+// raw encoding for its public keys, signatures and private keys.
+package keys
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Scheme identifies a signature scheme
+type Scheme byte
+
+const (
+	// P256 is ECDSA over NIST P-256. Public keys are 33-byte compressed points,
+	// signatures are r || s, 64 bytes.
+	P256 Scheme = 1
+	// Secp256k1 is ECDSA over secp256k1 with RFC 6979 nonces. Public keys are 33-byte
+	// compressed points, signatures are r || s, 64 bytes.
+	Secp256k1 Scheme = 2
+	// Ed25519 is EdDSA over Curve25519. Public keys are 32 bytes, signatures 64 bytes.
+	Ed25519 Scheme = 3
+	// Multisig is an M-of-N account of keys of the other schemes, see MultisigKey.
+	// It has no private key: its members sign.
+	Multisig Scheme = 4
+)
+
+// DefaultScheme is used for new wallets unless another one is chosen
+const DefaultScheme = P256
+
+// Schemes lists every scheme that keys can be generated for
+var Schemes = []Scheme{P256, Secp256k1, Ed25519}
+
+// PublicKey verifies signatures made with the matching PrivateKey
+type PublicKey interface {
+	Scheme() Scheme
+	// Bytes returns the compact raw encoding of the key
+	Bytes() []byte
+	// Verify reports whether sig is a valid signature of hash
+	Verify(hash, sig []byte) bool
+	// Address returns the account address controlled by the key
+	Address() string
+}
+
+// PrivateKey signs transaction hashes
+type PrivateKey interface {
+	Scheme() Scheme
+	Public() PublicKey
+	// Bytes returns the raw private key: the scalar for ECDSA, the seed for Ed25519
+	Bytes() []byte
+	// Sign signs a 32-byte hash
+	Sign(hash []byte) ([]byte, error)
+}
+
+// RecoverablePrivateKey is a private key whose signatures identify its public key
+type RecoverablePrivateKey interface {
+	PrivateKey
+	// SignRecoverable signs a 32-byte hash so the public key can be recovered from the signature
+	SignRecoverable(hash []byte) ([]byte, error)
+}
+
+// Signer signs hashes for a public key. Private keys are signers, as are keys held
+// by another process that only hands out signatures.
+type Signer interface {
+	Public() PublicKey
+	// Sign signs a 32-byte hash
+	Sign(hash []byte) ([]byte, error)
+}
+
+// RecoverableSigner is a signer that can make signatures identifying its public key
+type RecoverableSigner interface {
+	Signer
+	// SignRecoverable signs a 32-byte hash so the public key can be recovered from the signature
+	SignRecoverable(hash []byte) ([]byte, error)
+}
+
+// Recoverable reports whether public keys of the scheme can be recovered from its
+// signatures. Only secp256k1 supports it.
+func Recoverable(s Scheme) bool {
+	return s == Secp256k1
+}
+
+// RecoverPublicKey returns the public key that made the recoverable signature sig of hash.
+// It fails if the signature isn't valid.
+func RecoverPublicKey(s Scheme, hash, sig []byte) (PublicKey, error) {
+	if s != Secp256k1 {
+		return nil, fmt.Errorf("%s signatures aren't recoverable", s)
+	}
+	return recoverSecp256k1(hash, sig)
+}
+
+var errMultisigPrivateKey = errors.New("multisig accounts have no private key, their members sign")
+
+// String returns the name of the scheme as used on the command line
+func (s Scheme) String() string {
+	switch s {
+	case P256:
+		return "p256"
+	case Secp256k1:
+		return "secp256k1"
+	case Ed25519:
+		return "ed25519"
+	case Multisig:
+		return "multisig"
+	}
+	return fmt.Sprintf("scheme(%d)", byte(s))
+}
+
+// ParseScheme returns the scheme with the given name
+func ParseScheme(name string) (Scheme, error) {
+	for _, s := range Schemes {
+		if strings.EqualFold(name, s.String()) {
+			return s, nil
+		}
+	}
+	if strings.EqualFold(name, Multisig.String()) {
+		return Multisig, nil
+	}
+	return 0, fmt.Errorf("unknown signature scheme %q (want p256, secp256k1 or ed25519)", name)
+}
+
+// GenerateKey returns a new random private key
+func GenerateKey(s Scheme) (PrivateKey, error) {
+	switch s {
+	case P256:
+		return generateP256()
+	case Secp256k1:
+		return generateSecp256k1()
+	case Ed25519:
+		return generateEd25519()
+	case Multisig:
+		return nil, errMultisigPrivateKey
+	}
+	return nil, fmt.Errorf("unsupported signature scheme %d", byte(s))
+}
+
+// ParsePublicKey decodes the raw encoding of a public key
+func ParsePublicKey(s Scheme, data []byte) (PublicKey, error) {
+	switch s {
+	case P256:
+		return parseP256PublicKey(data)
+	case Secp256k1:
+		return parseSecp256k1PublicKey(data)
+	case Ed25519:
+		return parseEd25519PublicKey(data)
+	case Multisig:
+		return parseMultisigKey(data)
+	}
+	return nil, fmt.Errorf("unsupported signature scheme %d", byte(s))
+}
+
+// ParsePrivateKey decodes the raw encoding of a private key
+func ParsePrivateKey(s Scheme, data []byte) (PrivateKey, error) {
+	switch s {
+	case P256:
+		return parseP256PrivateKey(data)
+	case Secp256k1:
+		return parseSecp256k1PrivateKey(data)
+	case Ed25519:
+		return parseEd25519PrivateKey(data)
+	case Multisig:
+		return nil, errMultisigPrivateKey
+	}
+	return nil, fmt.Errorf("unsupported signature scheme %d", byte(s))
+}
+
+// ParsePEMPublicKey decodes a PEM-encoded PKIX P-256 public key, the sender
+// encoding of transactions from before schemes were pluggable
+func ParsePEMPublicKey(data []byte) (PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("invalid PEM block for public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pk, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not a valid ECDSA public key")
+	}
+	return FromECDSA(pk)
+}
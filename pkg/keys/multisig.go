@@ -0,0 +1,221 @@
+package keys
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// MaxMultisigKeys is the largest number of members a multisig account can have
+const MaxMultisigKeys = 16
+
+// MultisigKey is the public key of an M-of-N multisig account: a threshold and the
+// member keys, of any of the other schemes, sorted by their encoding.
+//
+// Its raw encoding is the threshold and member count bytes followed, for every
+// member, by its scheme byte, key length byte and raw key. A signature is the list
+// of member signatures, each encoded as the member index, signature length and
+// signature, in increasing index order.
+type MultisigKey struct {
+	threshold int
+	members   []PublicKey
+}
+
+// NewMultisigKey returns the multisig key needing threshold signatures of the given
+// members. The order of members doesn't matter: the same set always gives the same key.
+func NewMultisigKey(threshold int, members []PublicKey) (*MultisigKey, error) {
+	if len(members) == 0 || len(members) > MaxMultisigKeys {
+		return nil, fmt.Errorf("a multisig account needs 1 to %d keys, not %d", MaxMultisigKeys, len(members))
+	}
+	if threshold < 1 || threshold > len(members) {
+		return nil, fmt.Errorf("threshold must be between 1 and %d, not %d", len(members), threshold)
+	}
+
+	sorted := append([]PublicKey(nil), members...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(memberBytes(sorted[i]), memberBytes(sorted[j])) < 0
+	})
+	for i, m := range sorted {
+		if m.Scheme() == Multisig {
+			return nil, errors.New("multisig accounts can't be members of a multisig account")
+		}
+		if i > 0 && bytes.Equal(memberBytes(m), memberBytes(sorted[i-1])) {
+			return nil, errors.New("duplicate key in multisig account")
+		}
+	}
+	return &MultisigKey{threshold: threshold, members: sorted}, nil
+}
+
+// memberBytes is the sort key of a member: its scheme byte and raw key
+func memberBytes(pub PublicKey) []byte {
+	return append([]byte{byte(pub.Scheme())}, pub.Bytes()...)
+}
+
+func parseMultisigKey(data []byte) (PublicKey, error) {
+	if len(data) < 2 {
+		return nil, errors.New("invalid multisig key")
+	}
+	threshold, n := int(data[0]), int(data[1])
+	rest := data[2:]
+	members := make([]PublicKey, 0, n)
+	for i := 0; i < n; i++ {
+		if len(rest) < 2 || len(rest) < 2+int(rest[1]) {
+			return nil, errors.New("invalid multisig key: truncated member")
+		}
+		pub, err := ParsePublicKey(Scheme(rest[0]), rest[2:2+int(rest[1])])
+		if err != nil {
+			return nil, fmt.Errorf("invalid multisig member %d: %w", i, err)
+		}
+		members = append(members, pub)
+		rest = rest[2+int(rest[1]):]
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("invalid multisig key: trailing data")
+	}
+
+	k, err := NewMultisigKey(threshold, members)
+	if err != nil {
+		return nil, err
+	}
+	// Only the sorted encoding is accepted, so an account has a single address
+	if !bytes.Equal(k.Bytes(), data) {
+		return nil, errors.New("invalid multisig key: members aren't sorted")
+	}
+	return k, nil
+}
+
+// Threshold returns the number of member signatures a transaction needs
+func (k *MultisigKey) Threshold() int { return k.threshold }
+
+// Members returns the member keys in their canonical order
+func (k *MultisigKey) Members() []PublicKey { return append([]PublicKey(nil), k.members...) }
+
+// Index returns the position of pub among the members, or -1 if it isn't one
+func (k *MultisigKey) Index(pub PublicKey) int {
+	for i, m := range k.members {
+		if bytes.Equal(memberBytes(m), memberBytes(pub)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (k *MultisigKey) Scheme() Scheme { return Multisig }
+
+func (k *MultisigKey) Bytes() []byte {
+	out := []byte{byte(k.threshold), byte(len(k.members))}
+	for _, m := range k.members {
+		raw := m.Bytes()
+		out = append(out, byte(m.Scheme()), byte(len(raw)))
+		out = append(out, raw...)
+	}
+	return out
+}
+
+// Verify reports whether sig holds valid signatures of hash by at least threshold
+// distinct members. A signature with any invalid member signature is rejected.
+func (k *MultisigKey) Verify(hash, sig []byte) bool {
+	sigs, err := DecodeMultiSignature(sig)
+	if err != nil || len(sigs) < k.threshold {
+		return false
+	}
+	for i, s := range sigs {
+		if i >= len(k.members) || !k.members[i].Verify(hash, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// Address hashes the scheme byte and the encoded threshold and members with SHA256
+func (k *MultisigKey) Address() string {
+	return schemeAddress(Multisig, k.Bytes())
+}
+
+// AddSignature signs hash with signer, which must hold the key of a member, and
+// returns the multisig signature sig with that signature added
+func (k *MultisigKey) AddSignature(sig []byte, signer Signer, hash []byte) ([]byte, error) {
+	i := k.Index(signer.Public())
+	if i < 0 {
+		return nil, errors.New("key isn't a member of the multisig account")
+	}
+	sigs, err := DecodeMultiSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	s, err := signer.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+	sigs[i] = s
+	return EncodeMultiSignature(sigs), nil
+}
+
+// Signers returns the members that made the signatures in sig, valid or not
+func (k *MultisigKey) Signers(sig []byte) ([]PublicKey, error) {
+	sigs, err := DecodeMultiSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	var out []PublicKey
+	for i := range k.members {
+		if _, ok := sigs[i]; ok {
+			out = append(out, k.members[i])
+		}
+	}
+	return out, nil
+}
+
+// CombineSignatures merges the member signatures of several multisig signatures
+// of the same hash. Where two hold a signature of the same member, the first one wins.
+func CombineSignatures(sigs ...[]byte) ([]byte, error) {
+	out := make(map[int][]byte)
+	for _, sig := range sigs {
+		decoded, err := DecodeMultiSignature(sig)
+		if err != nil {
+			return nil, err
+		}
+		for i, s := range decoded {
+			if _, ok := out[i]; !ok {
+				out[i] = s
+			}
+		}
+	}
+	return EncodeMultiSignature(out), nil
+}
+
+// DecodeMultiSignature splits a multisig signature into the signatures of each member index
+func DecodeMultiSignature(sig []byte) (map[int][]byte, error) {
+	out := make(map[int][]byte)
+	last := -1
+	for len(sig) > 0 {
+		if len(sig) < 2 || len(sig) < 2+int(sig[1]) {
+			return nil, errors.New("invalid multisig signature: truncated entry")
+		}
+		i := int(sig[0])
+		if i <= last || i >= MaxMultisigKeys {
+			return nil, errors.New("invalid multisig signature: member indexes out of order")
+		}
+		out[i] = append([]byte(nil), sig[2:2+int(sig[1])]...)
+		last = i
+		sig = sig[2+int(sig[1]):]
+	}
+	return out, nil
+}
+
+// EncodeMultiSignature encodes the signatures of each member index as a multisig signature
+func EncodeMultiSignature(sigs map[int][]byte) []byte {
+	indexes := make([]int, 0, len(sigs))
+	for i := range sigs {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	var out []byte
+	for _, i := range indexes {
+		out = append(out, byte(i), byte(len(sigs[i])))
+		out = append(out, sigs[i]...)
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+// testMultisig returns a 2-of-3 multisig key over members of every scheme, and
+// their private keys in the multisig's member order
+func testMultisig(t *testing.T) (*MultisigKey, []PrivateKey) {
+	t.Helper()
+	var privs []PrivateKey
+	var pubs []PublicKey
+	for _, s := range Schemes {
+		priv, err := GenerateKey(s)
+		if err != nil {
+			t.Fatalf("GenerateKey(%s): %v", s, err)
+		}
+		privs = append(privs, priv)
+		pubs = append(pubs, priv.Public())
+	}
+	k, err := NewMultisigKey(2, pubs)
+	if err != nil {
+		t.Fatalf("NewMultisigKey: %v", err)
+	}
+
+	ordered := make([]PrivateKey, len(privs))
+	for _, priv := range privs {
+		ordered[k.Index(priv.Public())] = priv
+	}
+	return k, ordered
+}
+
+func TestMultisigVerify(t *testing.T) {
+	k, privs := testMultisig(t)
+	hash := sha256.Sum256([]byte("tx"))
+
+	one, err := k.AddSignature(nil, privs[2], hash[:])
+	if err != nil {
+		t.Fatalf("AddSignature: %v", err)
+	}
+	if k.Verify(hash[:], one) {
+		t.Errorf("1 of 2 required signatures accepted")
+	}
+
+	two, err := k.AddSignature(one, privs[0], hash[:])
+	if err != nil {
+		t.Fatalf("AddSignature: %v", err)
+	}
+	if !k.Verify(hash[:], two) {
+		t.Errorf("2 of 2 required signatures rejected")
+	}
+	other := sha256.Sum256([]byte("another tx"))
+	if k.Verify(other[:], two) {
+		t.Errorf("signatures accepted for another hash")
+	}
+
+	// A bad member signature spoils the whole signature, even with enough good ones
+	sigs, _ := DecodeMultiSignature(two)
+	sigs[1] = bytes.Repeat([]byte{1}, 64)
+	if k.Verify(hash[:], EncodeMultiSignature(sigs)) {
+		t.Errorf("signature with an invalid member signature accepted")
+	}
+
+	// The same member signing twice doesn't count twice
+	dup := append(append([]byte{}, encodeEntry(0, sigs[0])...), encodeEntry(0, sigs[0])...)
+	if k.Verify(hash[:], dup) {
+		t.Errorf("one member's signature counted twice")
+	}
+
+	// Signatures for an index past the members are rejected
+	sigs, _ = DecodeMultiSignature(two)
+	sigs[len(k.Members())] = sigs[0]
+	if k.Verify(hash[:], EncodeMultiSignature(sigs)) {
+		t.Errorf("signature of a non-existent member accepted")
+	}
+
+	if _, err := k.AddSignature(nil, mustGenerate(t, P256), hash[:]); err == nil {
+		t.Errorf("AddSignature accepted a key that isn't a member")
+	}
+}
+
+func TestDecodeMultiSignature(t *testing.T) {
+	sig := []byte{7, 7, 7}
+	tests := []struct {
+		name string
+		data []byte
+		ok   bool
+	}{
+		{"empty", nil, true},
+		{"increasing", append(encodeEntry(0, sig), encodeEntry(2, sig)...), true},
+		{"duplicate index", append(encodeEntry(1, sig), encodeEntry(1, sig)...), false},
+		{"decreasing", append(encodeEntry(2, sig), encodeEntry(0, sig)...), false},
+		{"index too large", encodeEntry(MaxMultisigKeys, sig), false},
+		{"truncated", encodeEntry(0, sig)[:4], false},
+	}
+	for _, tc := range tests {
+		_, err := DecodeMultiSignature(tc.data)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+	}
+}
+
+func TestParseMultisigKey(t *testing.T) {
+	k, _ := testMultisig(t)
+
+	parsed, err := ParsePublicKey(Multisig, k.Bytes())
+	if err != nil {
+		t.Fatalf("ParsePublicKey: %v", err)
+	}
+	if parsed.Address() != k.Address() {
+		t.Errorf("parsed key has another address")
+	}
+
+	// Swap the first two members: the same account, but not in canonical order
+	members := k.Members()
+	swapped := []byte{byte(k.Threshold()), byte(len(members))}
+	for _, i := range []int{1, 0, 2} {
+		raw := members[i].Bytes()
+		swapped = append(swapped, byte(members[i].Scheme()), byte(len(raw)))
+		swapped = append(swapped, raw...)
+	}
+	if _, err := parseMultisigKey(swapped); err == nil {
+		t.Errorf("members out of canonical order accepted")
+	}
+
+	if _, err := parseMultisigKey(append(k.Bytes(), 0)); err == nil {
+		t.Errorf("trailing data accepted")
+	}
+	if _, err := parseMultisigKey(k.Bytes()[:10]); err == nil {
+		t.Errorf("truncated key accepted")
+	}
+
+	bad := k.Bytes()
+	bad[0] = byte(len(members) + 1)
+	if _, err := parseMultisigKey(bad); err == nil {
+		t.Errorf("threshold above the member count accepted")
+	}
+
+	if _, err := NewMultisigKey(1, []PublicKey{members[0], members[0]}); err == nil {
+		t.Errorf("duplicate member accepted")
+	}
+	if _, err := NewMultisigKey(1, []PublicKey{members[0], k}); err == nil {
+		t.Errorf("nested multisig accepted")
+	}
+}
+
+func TestCombineSignatures(t *testing.T) {
+	k, privs := testMultisig(t)
+	hash := sha256.Sum256([]byte("tx"))
+
+	var partial [][]byte
+	for _, priv := range privs {
+		sig, err := k.AddSignature(nil, priv, hash[:])
+		if err != nil {
+			t.Fatalf("AddSignature: %v", err)
+		}
+		if k.Verify(hash[:], sig) {
+			t.Fatalf("a single signature meets the threshold")
+		}
+		partial = append(partial, sig)
+	}
+
+	combined, err := CombineSignatures(partial[2], partial[0])
+	if err != nil {
+		t.Fatalf("CombineSignatures: %v", err)
+	}
+	if !k.Verify(hash[:], combined) {
+		t.Errorf("combined signatures rejected")
+	}
+	signers, _ := k.Signers(combined)
+	if len(signers) != 2 || k.Index(signers[0]) != 0 || k.Index(signers[1]) != 2 {
+		t.Errorf("combined signers: got %d of them", len(signers))
+	}
+
+	// Where both hold a signature of the same member, the first one wins
+	sigs, _ := DecodeMultiSignature(partial[0])
+	sigs[0] = []byte{9}
+	first, _ := CombineSignatures(partial[0], EncodeMultiSignature(sigs))
+	if !bytes.Equal(first, partial[0]) {
+		t.Errorf("first signature of a member was replaced")
+	}
+
+	if _, err := CombineSignatures(partial[0], []byte{5}); err == nil {
+		t.Errorf("malformed signature combined")
+	}
+}
+
+func encodeEntry(index int, sig []byte) []byte {
+	return append([]byte{byte(index), byte(len(sig))}, sig...)
+}
+
+func mustGenerate(t *testing.T, s Scheme) PrivateKey {
+	t.Helper()
+	priv, err := GenerateKey(s)
+	if err != nil {
+		t.Fatalf("GenerateKey(%s): %v", s, err)
+	}
+	return priv
+}
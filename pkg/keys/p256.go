@@ -0,0 +1,111 @@
+package keys
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+)
+
+type p256PublicKey struct {
+	key *ecdsa.PublicKey
+}
+
+type p256PrivateKey struct {
+	key *ecdsa.PrivateKey
+}
+
+// FromECDSA wraps a P-256 ECDSA public key
+func FromECDSA(pub *ecdsa.PublicKey) (PublicKey, error) {
+	if pub.Curve != elliptic.P256() {
+		return nil, errors.New("not a P-256 key")
+	}
+	return &p256PublicKey{key: pub}, nil
+}
+
+// FromECDSAPrivate wraps a P-256 ECDSA private key
+func FromECDSAPrivate(priv *ecdsa.PrivateKey) (PrivateKey, error) {
+	if priv.Curve != elliptic.P256() {
+		return nil, errors.New("not a P-256 key")
+	}
+	return &p256PrivateKey{key: priv}, nil
+}
+
+func generateP256() (PrivateKey, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &p256PrivateKey{key: priv}, nil
+}
+
+func parseP256PublicKey(data []byte) (PublicKey, error) {
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), data)
+	if x == nil {
+		return nil, errors.New("invalid P-256 public key")
+	}
+	return &p256PublicKey{key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}}, nil
+}
+
+func parseP256PrivateKey(data []byte) (PrivateKey, error) {
+	// ecdh checks the length and range of the scalar and computes the public point
+	k, err := ecdh.P256().NewPrivateKey(data)
+	if err != nil {
+		return nil, errors.New("invalid P-256 private key")
+	}
+	// The uncompressed encoding is 0x04 || X || Y
+	raw := k.PublicKey().Bytes()
+	priv := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(raw[1:33]),
+			Y:     new(big.Int).SetBytes(raw[33:]),
+		},
+		D: new(big.Int).SetBytes(data),
+	}
+	return &p256PrivateKey{key: priv}, nil
+}
+
+func (k *p256PublicKey) Scheme() Scheme { return P256 }
+
+func (k *p256PublicKey) Bytes() []byte {
+	return elliptic.MarshalCompressed(elliptic.P256(), k.key.X, k.key.Y)
+}
+
+func (k *p256PublicKey) Verify(hash, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	return ecdsa.Verify(k.key, hash, r, s)
+}
+
+// Address hashes the X and Y coordinates with SHA256, as addresses were computed
+// before schemes were pluggable, so existing P-256 accounts keep their address
+func (k *p256PublicKey) Address() string {
+	pubBytes := append(k.key.X.Bytes(), k.key.Y.Bytes()...)
+	hash := sha256.Sum256(pubBytes)
+	return hex.EncodeToString(hash[:])
+}
+
+func (k *p256PrivateKey) Scheme() Scheme { return P256 }
+
+func (k *p256PrivateKey) Public() PublicKey { return &p256PublicKey{key: &k.key.PublicKey} }
+
+func (k *p256PrivateKey) Bytes() []byte { return k.key.D.FillBytes(make([]byte, 32)) }
+
+func (k *p256PrivateKey) Sign(hash []byte) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, k.key, hash)
+	if err != nil {
+		return nil, err
+	}
+	// Normalize the signature to fixed 64 bytes: 32 bytes for R + 32 bytes for S
+	rBytes := r.FillBytes(make([]byte, 32))
+	sBytes := s.FillBytes(make([]byte, 32))
+	return append(rBytes, sBytes...), nil
+}
@@ -0,0 +1,108 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secpecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+type secp256k1PublicKey struct {
+	key *secp256k1.PublicKey
+}
+
+type secp256k1PrivateKey struct {
+	key *secp256k1.PrivateKey
+}
+
+func generateSecp256k1() (PrivateKey, error) {
+	priv, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &secp256k1PrivateKey{key: priv}, nil
+}
+
+func parseSecp256k1PublicKey(data []byte) (PublicKey, error) {
+	if len(data) != secp256k1.PubKeyBytesLenCompressed {
+		return nil, errors.New("invalid secp256k1 public key length")
+	}
+	pub, err := secp256k1.ParsePubKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return &secp256k1PublicKey{key: pub}, nil
+}
+
+func parseSecp256k1PrivateKey(data []byte) (PrivateKey, error) {
+	if len(data) != secp256k1.PrivKeyBytesLen {
+		return nil, errors.New("invalid secp256k1 private key length")
+	}
+	var d secp256k1.ModNScalar
+	if overflow := d.SetByteSlice(data); overflow || d.IsZero() {
+		return nil, errors.New("invalid secp256k1 private key")
+	}
+	return &secp256k1PrivateKey{key: secp256k1.NewPrivateKey(&d)}, nil
+}
+
+func (k *secp256k1PublicKey) Scheme() Scheme { return Secp256k1 }
+
+func (k *secp256k1PublicKey) Bytes() []byte { return k.key.SerializeCompressed() }
+
+func (k *secp256k1PublicKey) Verify(hash, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+	var r, s secp256k1.ModNScalar
+	if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) || r.IsZero() || s.IsZero() {
+		return false
+	}
+	return secpecdsa.NewSignature(&r, &s).Verify(hash, k.key)
+}
+
+// Address hashes the scheme byte and the compressed key with SHA256, so it can't
+// collide with the address of a key of another scheme
+func (k *secp256k1PublicKey) Address() string {
+	return schemeAddress(Secp256k1, k.Bytes())
+}
+
+func (k *secp256k1PrivateKey) Scheme() Scheme { return Secp256k1 }
+
+func (k *secp256k1PrivateKey) Public() PublicKey { return &secp256k1PublicKey{key: k.key.PubKey()} }
+
+func (k *secp256k1PrivateKey) Bytes() []byte { return k.key.Serialize() }
+
+func (k *secp256k1PrivateKey) Sign(hash []byte) ([]byte, error) {
+	sig := secpecdsa.Sign(k.key, hash)
+	r, s := sig.R(), sig.S()
+	out := make([]byte, 64)
+	r.PutBytesUnchecked(out[:32])
+	s.PutBytesUnchecked(out[32:])
+	return out, nil
+}
+
+// SignRecoverable returns a 65-byte signature: a recovery byte followed by r || s
+func (k *secp256k1PrivateKey) SignRecoverable(hash []byte) ([]byte, error) {
+	return secpecdsa.SignCompact(k.key, hash, true), nil
+}
+
+// recoverSecp256k1 recovers the key of a signature made by SignRecoverable.
+// Signatures for uncompressed keys are rejected, so every key has one encoding.
+func recoverSecp256k1(hash, sig []byte) (PublicKey, error) {
+	pub, compressed, err := secpecdsa.RecoverCompact(sig, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !compressed {
+		return nil, errors.New("recoverable signature isn't for a compressed key")
+	}
+	return &secp256k1PublicKey{key: pub}, nil
+}
+
+// schemeAddress is the address of keys of schemes added after P-256
+func schemeAddress(s Scheme, pub []byte) string {
+	hash := sha256.Sum256(append([]byte{byte(s)}, pub...))
+	return hex.EncodeToString(hash[:])
+}
@@ -2,17 +2,21 @@ package p2p
 
 import (
 	"context"
+	"fmt"
+	"golang-chain/pkg/blockchain"
 	"golang-chain/pkg/p2p/pb"
 	"golang-chain/pkg/storage"
 	"log"
+	"strconv"
+	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// SendBlockForVote sends a proposed block to all follower peers
+// SendBlockForVote sends a block proposal to all follower peers
 // and collects their votes (approve/reject). Used by the Leader.
-func SendBlockForVote(peers []string, block *pb.Block) []*pb.VoteResponse {
+func SendBlockForVote(peers []string, req *pb.VoteRequest) []*pb.VoteResponse {
 	var votes []*pb.VoteResponse
 
 	for _, addr := range peers {
@@ -26,7 +30,7 @@ func SendBlockForVote(peers []string, block *pb.Block) []*pb.VoteResponse {
 
 		// Create client and send vote request
 		client := pb.NewNodeServiceClient(conn)
-		vote, err := client.ProposeBlock(context.Background(), &pb.VoteRequest{Block: block})
+		vote, err := client.ProposeBlock(context.Background(), req)
 		if err != nil {
 			log.Println("Peer failed to vote:", err)
 			continue
@@ -40,8 +44,8 @@ func SendBlockForVote(peers []string, block *pb.Block) []*pb.VoteResponse {
 }
 
 // BroadcastCommit is called by the Leader after receiving enough votes.
-// It sends the finalized block to all peers, telling them to save it.
-func BroadcastCommit(peers []string, block *pb.Block) {
+// It sends the finalized block with its proposal and votes to all peers, telling them to save it.
+func BroadcastCommit(peers []string, req *pb.CommitRequest) {
 	for _, addr := range peers {
 		conn, err := grpc.Dial(addr, grpc.WithInsecure())
 		if err != nil {
@@ -51,7 +55,7 @@ func BroadcastCommit(peers []string, block *pb.Block) {
 		defer conn.Close()
 
 		client := pb.NewNodeServiceClient(conn)
-		_, err = client.CommitBlock(context.Background(), block)
+		_, err = client.CommitBlock(context.Background(), req)
 		if err != nil {
 			log.Println("Commit failed to", addr)
 		} else {
@@ -136,6 +140,12 @@ func SyncFromPeerByHeight(peer string, db *storage.DB) {
 	leaderHeight := latestResp.Block.Height
 	log.Printf("🌐 Peer has block height: %d", leaderHeight)
 
+	// A pruning peer can't serve blocks below its retained range
+	if rng, err := client.GetRetainedRange(context.Background(), &pb.Empty{}); err == nil && start < rng.BodiesFrom {
+		log.Printf("❌ Peer only keeps blocks from height %d, can't sync from %d (try FAST_SYNC=true)", rng.BodiesFrom, start)
+		return
+	}
+
 	// 3. Loop through each missing block and fetch it from the peer
 	for h := start; h <= leaderHeight; h++ {
 		resp, err := client.GetBlockByHeight(context.Background(), &pb.HeightRequest{Height: h})
@@ -155,3 +165,153 @@ func SyncFromPeerByHeight(peer string, db *storage.DB) {
 	}
 	log.Println("🎉 Sync completed successfully.")
 }
+
+// Checkpoint is a block hash at a given height that the operator trusts,
+// e.g. read from a node they run themselves
+type Checkpoint struct {
+	Height int64
+	Hash   string
+}
+
+// ParseCheckpoint parses a checkpoint written as <height>:<block hash>
+func ParseCheckpoint(s string) (*Checkpoint, error) {
+	height, hash, ok := strings.Cut(s, ":")
+	if !ok || hash == "" {
+		return nil, fmt.Errorf("checkpoint %q isn't <height>:<block hash>", s)
+	}
+	h, err := strconv.ParseInt(height, 10, 64)
+	if err != nil || h < 1 {
+		return nil, fmt.Errorf("invalid checkpoint height %q", height)
+	}
+	return &Checkpoint{Height: h, Hash: hash}, nil
+}
+
+// FastSyncFromPeer downloads the newest state snapshot of a peer and restores it,
+// so that only the blocks after the snapshot have to be synced. The snapshot is checked
+// chunk by chunk against its manifest and as a whole against the snapshot block's state root.
+// The snapshot block comes from the same peer, so it's only trusted once the peer's headers
+// link it back to the local genesis block, through the checkpoint if one is given.
+// Only works on a node that holds nothing beyond the genesis block.
+func FastSyncFromPeer(peer string, db *storage.DB, checkpoint *Checkpoint) error {
+	log.Println("⚡ Fast syncing from peer:", peer)
+
+	conn, err := grpc.Dial(peer, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("connect error: %w", err)
+	}
+	defer conn.Close()
+
+	client := pb.NewNodeServiceClient(conn)
+
+	// 1. Pick the newest snapshot the peer offers
+	list, err := client.ListSnapshots(context.Background(), &pb.Empty{})
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(list.Snapshots) == 0 {
+		return fmt.Errorf("peer has no snapshots")
+	}
+	m := list.Snapshots[0]
+	manifest := &storage.SnapshotManifest{
+		Height:    m.Height,
+		BlockHash: m.BlockHash,
+		StateRoot: m.StateRoot,
+		Chunks:    m.Chunks,
+	}
+	log.Printf("📸 Peer offers a snapshot at height %d (%d chunks)", manifest.Height, len(manifest.Chunks))
+
+	// 2. Fetch the block the snapshot belongs to
+	resp, err := client.GetBlockByHeight(context.Background(), &pb.HeightRequest{Height: manifest.Height})
+	if err != nil || resp.Block == nil {
+		return fmt.Errorf("failed to get snapshot block at height %d: %v", manifest.Height, err)
+	}
+	block := convertPbBlock(resp.Block)
+	if checkpoint != nil && checkpoint.Height > block.Height {
+		return fmt.Errorf("snapshot at height %d is below the checkpoint at height %d", block.Height, checkpoint.Height)
+	}
+	if err := verifyHeaderChain(client, db, block, checkpoint); err != nil {
+		return fmt.Errorf("snapshot block isn't linked to the local chain: %w", err)
+	}
+
+	// 3. Download every chunk, rejecting a bad one as soon as it arrives
+	var chunks [][]byte
+	for i := range manifest.Chunks {
+		chunk, err := client.GetSnapshotChunk(context.Background(), &pb.SnapshotChunkRequest{Height: manifest.Height, Index: int32(i)})
+		if err != nil {
+			return fmt.Errorf("failed to get chunk %d: %w", i, err)
+		}
+		if err := storage.VerifySnapshotChunk(manifest, i, chunk.Data); err != nil {
+			return err
+		}
+		chunks = append(chunks, chunk.Data)
+		log.Printf("📥 Got snapshot chunk %d/%d", i+1, len(manifest.Chunks))
+	}
+
+	// 4. Check the whole state against the block and store it
+	if err := db.RestoreSnapshot(block, manifest, chunks); err != nil {
+		return err
+	}
+	log.Printf("✅ Restored state snapshot at height %d", manifest.Height)
+	return nil
+}
+
+// verifyHeaderChain fetches the peer's headers from height 1 up to the block and checks that
+// each one hashes to its own hash and points to the one before, starting at the local
+// genesis block, and that the header at the checkpoint height has the checkpoint hash.
+// Without a checkpoint the peer is trusted to serve the real chain, which is logged.
+func verifyHeaderChain(client pb.NodeServiceClient, db *storage.DB, block *blockchain.Block, checkpoint *Checkpoint) error {
+	genesis, err := db.GetBlockByHeight(0)
+	if err != nil {
+		return fmt.Errorf("failed to read the local genesis block: %w", err)
+	}
+	if blockchain.HashBlock(block) != block.CurrentBlockHash {
+		return fmt.Errorf("block %d doesn't match its hash", block.Height)
+	}
+	if checkpoint == nil {
+		// Headers carry no signatures, so a chain that links up proves nothing by itself
+		log.Printf("⚠️ No FAST_SYNC_CHECKPOINT set: the snapshot block at height %d is only vouched for by this one peer, which could serve a made-up chain", block.Height)
+	}
+
+	prevHash := genesis.CurrentBlockHash
+	for h := int64(1); h <= block.Height; h++ {
+		resp, err := client.GetHeaderByHeight(context.Background(), &pb.HeightRequest{Height: h})
+		if err != nil {
+			return fmt.Errorf("failed to get header at height %d: %w", h, err)
+		}
+		header := PbToHeader(resp)
+		if header.Height != h || header.PrevBlockHash != prevHash {
+			return fmt.Errorf("header at height %d doesn't follow block %s", h, prevHash)
+		}
+		if err := checkHeaderHash(client, header); err != nil {
+			return err
+		}
+		if checkpoint != nil && h == checkpoint.Height && header.CurrentBlockHash != checkpoint.Hash {
+			return fmt.Errorf("block at height %d is %s, checkpoint says %s", h, header.CurrentBlockHash, checkpoint.Hash)
+		}
+		prevHash = header.CurrentBlockHash
+	}
+	if block.Height > 0 && prevHash != block.CurrentBlockHash {
+		return fmt.Errorf("header at height %d doesn't match block %s", block.Height, block.CurrentBlockHash)
+	}
+	return nil
+}
+
+// checkHeaderHash checks that a header hashes to its own hash. A version 1 block hash
+// covers the transactions too, so the whole block is fetched for those.
+func checkHeaderHash(client pb.NodeServiceClient, header *blockchain.BlockHeader) error {
+	if header.Version >= blockchain.BlockVersion2 {
+		if blockchain.HashHeader(header) != header.CurrentBlockHash {
+			return fmt.Errorf("header at height %d doesn't match its hash", header.Height)
+		}
+		return nil
+	}
+	resp, err := client.GetBlockByHeight(context.Background(), &pb.HeightRequest{Height: header.Height})
+	if err != nil || resp.Block == nil {
+		return fmt.Errorf("failed to get version 1 block at height %d: %v", header.Height, err)
+	}
+	block := convertPbBlock(resp.Block)
+	if block.CurrentBlockHash != header.CurrentBlockHash || blockchain.HashBlock(block) != header.CurrentBlockHash {
+		return fmt.Errorf("block at height %d doesn't match its hash", header.Height)
+	}
+	return nil
+}
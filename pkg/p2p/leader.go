@@ -2,12 +2,11 @@ package p2p
 
 import (
 	"log"
-	"math/big"
 	"time"
 
 	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/p2p/pb"
 	"golang-chain/pkg/storage"
-	"golang-chain/pkg/wallet"
 )
 
 // StartLeaderLoop runs on the leader node and periodically checks for pending transactions.
@@ -28,53 +27,76 @@ func StartLeaderLoop(db *storage.DB, peers []string) {
 		}
 
 		log.Printf("📨 Found %d pending transaction(s). Creating new block...", len(pending))
+		proposeBlock(db, peers, pending)
+	}
+}
 
-		// 2. Get the latest block from the local DB to determine previous hash and height
-		latest, _ := db.GetLatestBlock()
+// proposeBlock creates a block from the pending transactions taken from the pool,
+// proposes it to followers and commits it if enough votes are received.
+// The transactions are in flight until the block is stored or given up.
+func proposeBlock(db *storage.DB, peers []string, pending []*blockchain.Transaction) {
+	defer blockchain.ClearInFlightTxs(nil)
 
-		prevHash := ""
-		newHeight := int64(0)
-		if latest != nil {
-			prevHash = latest.CurrentBlockHash
-			newHeight = latest.Height + 1
-		}
+	// 2. Get the latest block from the local DB to determine previous hash and height
+	latest, _ := db.GetLatestBlock()
 
-		// 3. Create a new block with the pending transactions
-		block := blockchain.NewBlock(pending, prevHash, newHeight)
-		pbBlock := ConvertBlockToPb(block)
+	prevHash := ""
+	newHeight := int64(0)
+	if latest != nil {
+		prevHash = latest.CurrentBlockHash
+		newHeight = latest.Height + 1
+	}
 
-		// 4. Propose the block to follower nodes and collect votes
-		votes := SendBlockForVote(peers, pbBlock)
-		approveCount := 0
-		for _, v := range votes {
-			if v.Approved {
-				approveCount++
-			}
+	// 3. Execute the pending transactions on top of the current state,
+	// dropping the ones that can't be applied, and create a new block with the rest
+	st, err := db.LoadState()
+	if err != nil {
+		log.Println("❌ Failed to load state:", err)
+		return
+	}
+	var txs []*blockchain.Transaction
+	for _, tx := range pending {
+		if err := st.ApplyTx(tx); err != nil {
+			log.Println("⚠️ Dropping transaction:", err)
+			continue
 		}
+		txs = append(txs, tx)
+	}
+	if len(txs) == 0 {
+		return
+	}
+	stateRoot, err := st.Root()
+	if err != nil {
+		log.Println("❌ Failed to compute state root:", err)
+		return
+	}
 
-		// 5. Commit the block if majority votes are received (>=2 out of 3 nodes)
-		if approveCount >= 2 {
-			BroadcastCommit(peers, pbBlock) // Notify followers to commit
-			log.Println("✅ Committed block at height", block.Height, "with", len(pending), "txs")
-			db.SaveBlock(block) // Save block locally
-
-			// ✅ Update balances
-			for _, tx := range block.Transactions {
-				sender := wallet.ResolveSenderName(tx.Sender)
-				receiver := string(tx.Receiver)
-
-				fromBal, _ := db.GetBalance(sender)
-				toBal, _ := db.GetBalance(receiver)
-
-				fromBal.Sub(fromBal, big.NewFloat(tx.Amount))
-				toBal.Add(toBal, big.NewFloat(tx.Amount))
+	block := blockchain.NewBlock(txs, prevHash, newHeight, stateRoot)
+	pbBlock := ConvertBlockToPb(block)
 
-				db.SetBalance(sender, fromBal)
-				db.SetBalance(receiver, toBal)
+	// 4. Propose the block to follower nodes and collect votes, signed with the
+	// validator key when the node has one
+	req := &pb.VoteRequest{Block: pbBlock}
+	if err := signProposal(req); err != nil {
+		log.Println("❌ Failed to sign block proposal:", err)
+		return
+	}
+	votes := SendBlockForVote(peers, req)
+	approveCount := countApprovals(votes, block.CurrentBlockHash)
+
+	// 5. Commit the block if majority votes are received (>=2 out of 3 nodes),
+	// passing the votes on so followers can check them
+	if approveCount >= CommitQuorum {
+		BroadcastCommit(peers, &pb.CommitRequest{Proposal: req, Votes: votes}) // Notify followers to commit
+		log.Println("✅ Committed block at height", block.Height, "with", len(txs), "txs")
+		// Save block locally, balances are applied in the same write. Its transactions
+		// stop being in flight in the same step, so nonce checks count them exactly once.
+		blockchain.ClearInFlightTxs(func() {
+			if err := db.SaveBlock(block); err != nil {
+				log.Println("❌ Failed to save committed block:", err)
 			}
-		} else {
-			log.Println("❌ Not enough votes to commit block at height", block.Height)
-		}
+		})
+	} else {
+		log.Println("❌ Not enough votes to commit block at height", block.Height)
 	}
-
 }
@@ -28,6 +28,10 @@ type Transaction struct {
 	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
 	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	Signature     []byte                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	KeyType       uint32                 `protobuf:"varint,6,opt,name=keyType,proto3" json:"keyType,omitempty"`
+	Version       int32                  `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	Nonce         uint64                 `protobuf:"varint,8,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Fee           float64                `protobuf:"fixed64,9,opt,name=fee,proto3" json:"fee,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -97,6 +101,34 @@ func (x *Transaction) GetSignature() []byte {
 	return nil
 }
 
+func (x *Transaction) GetKeyType() uint32 {
+	if x != nil {
+		return x.KeyType
+	}
+	return 0
+}
+
+func (x *Transaction) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Transaction) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *Transaction) GetFee() float64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
 type TxResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -192,6 +224,8 @@ type Block struct {
 	PrevBlockHash    string                 `protobuf:"bytes,3,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
 	CurrentBlockHash string                 `protobuf:"bytes,4,opt,name=currentBlockHash,proto3" json:"currentBlockHash,omitempty"`
 	Height           int64                  `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	StateRoot        string                 `protobuf:"bytes,6,opt,name=stateRoot,proto3" json:"stateRoot,omitempty"`
+	Version          int32                  `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -261,11 +295,30 @@ func (x *Block) GetHeight() int64 {
 	return 0
 }
 
+func (x *Block) GetStateRoot() string {
+	if x != nil {
+		return x.StateRoot
+	}
+	return ""
+}
+
+func (x *Block) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// VoteRequest asks a validator to vote on a block. The proposer signs
+// consensus.ProposalHash of the block when the node has a validator key.
 type VoteRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Block         *Block                 `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Block           *Block                 `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	ProposerKey     []byte                 `protobuf:"bytes,2,opt,name=proposerKey,proto3" json:"proposerKey,omitempty"`
+	ProposerKeyType uint32                 `protobuf:"varint,3,opt,name=proposerKeyType,proto3" json:"proposerKeyType,omitempty"`
+	Signature       []byte                 `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *VoteRequest) Reset() {
@@ -305,10 +358,36 @@ func (x *VoteRequest) GetBlock() *Block {
 	return nil
 }
 
+func (x *VoteRequest) GetProposerKey() []byte {
+	if x != nil {
+		return x.ProposerKey
+	}
+	return nil
+}
+
+func (x *VoteRequest) GetProposerKeyType() uint32 {
+	if x != nil {
+		return x.ProposerKeyType
+	}
+	return 0
+}
+
+func (x *VoteRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// VoteResponse is a vote, signed over consensus.VoteHash when the voter
+// has a validator key
 type VoteResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	NodeId        string                 `protobuf:"bytes,1,opt,name=nodeId,proto3" json:"nodeId,omitempty"`
 	Approved      bool                   `protobuf:"varint,2,opt,name=approved,proto3" json:"approved,omitempty"`
+	VoterKey      []byte                 `protobuf:"bytes,3,opt,name=voterKey,proto3" json:"voterKey,omitempty"`
+	VoterKeyType  uint32                 `protobuf:"varint,4,opt,name=voterKeyType,proto3" json:"voterKeyType,omitempty"`
+	Signature     []byte                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -357,6 +436,81 @@ func (x *VoteResponse) GetApproved() bool {
 	return false
 }
 
+func (x *VoteResponse) GetVoterKey() []byte {
+	if x != nil {
+		return x.VoterKey
+	}
+	return nil
+}
+
+func (x *VoteResponse) GetVoterKeyType() uint32 {
+	if x != nil {
+		return x.VoterKeyType
+	}
+	return 0
+}
+
+func (x *VoteResponse) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// CommitRequest tells a validator to save a block, along with the signed
+// proposal and the votes that got it committed
+type CommitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Proposal      *VoteRequest           `protobuf:"bytes,1,opt,name=proposal,proto3" json:"proposal,omitempty"`
+	Votes         []*VoteResponse        `protobuf:"bytes,2,rep,name=votes,proto3" json:"votes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitRequest) Reset() {
+	*x = CommitRequest{}
+	mi := &file_proto_node_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitRequest) ProtoMessage() {}
+
+func (x *CommitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitRequest.ProtoReflect.Descriptor instead.
+func (*CommitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CommitRequest) GetProposal() *VoteRequest {
+	if x != nil {
+		return x.Proposal
+	}
+	return nil
+}
+
+func (x *CommitRequest) GetVotes() []*VoteResponse {
+	if x != nil {
+		return x.Votes
+	}
+	return nil
+}
+
 type BlockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
@@ -366,7 +520,7 @@ type BlockRequest struct {
 
 func (x *BlockRequest) Reset() {
 	*x = BlockRequest{}
-	mi := &file_proto_node_proto_msgTypes[6]
+	mi := &file_proto_node_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -378,7 +532,7 @@ func (x *BlockRequest) String() string {
 func (*BlockRequest) ProtoMessage() {}
 
 func (x *BlockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_node_proto_msgTypes[6]
+	mi := &file_proto_node_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -391,7 +545,7 @@ func (x *BlockRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlockRequest.ProtoReflect.Descriptor instead.
 func (*BlockRequest) Descriptor() ([]byte, []int) {
-	return file_proto_node_proto_rawDescGZIP(), []int{6}
+	return file_proto_node_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *BlockRequest) GetHash() string {
@@ -410,7 +564,7 @@ type BlockResponse struct {
 
 func (x *BlockResponse) Reset() {
 	*x = BlockResponse{}
-	mi := &file_proto_node_proto_msgTypes[7]
+	mi := &file_proto_node_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -422,7 +576,7 @@ func (x *BlockResponse) String() string {
 func (*BlockResponse) ProtoMessage() {}
 
 func (x *BlockResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_node_proto_msgTypes[7]
+	mi := &file_proto_node_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,7 +589,7 @@ func (x *BlockResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlockResponse.ProtoReflect.Descriptor instead.
 func (*BlockResponse) Descriptor() ([]byte, []int) {
-	return file_proto_node_proto_rawDescGZIP(), []int{7}
+	return file_proto_node_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *BlockResponse) GetBlock() *Block {
@@ -454,7 +608,7 @@ type HeightRequest struct {
 
 func (x *HeightRequest) Reset() {
 	*x = HeightRequest{}
-	mi := &file_proto_node_proto_msgTypes[8]
+	mi := &file_proto_node_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -466,7 +620,7 @@ func (x *HeightRequest) String() string {
 func (*HeightRequest) ProtoMessage() {}
 
 func (x *HeightRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_node_proto_msgTypes[8]
+	mi := &file_proto_node_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -479,7 +633,7 @@ func (x *HeightRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeightRequest.ProtoReflect.Descriptor instead.
 func (*HeightRequest) Descriptor() ([]byte, []int) {
-	return file_proto_node_proto_rawDescGZIP(), []int{8}
+	return file_proto_node_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *HeightRequest) GetHeight() int64 {
@@ -491,14 +645,15 @@ func (x *HeightRequest) GetHeight() int64 {
 
 type BalanceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Prove         bool                   `protobuf:"varint,2,opt,name=prove,proto3" json:"prove,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BalanceRequest) Reset() {
 	*x = BalanceRequest{}
-	mi := &file_proto_node_proto_msgTypes[9]
+	mi := &file_proto_node_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -510,7 +665,7 @@ func (x *BalanceRequest) String() string {
 func (*BalanceRequest) ProtoMessage() {}
 
 func (x *BalanceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_node_proto_msgTypes[9]
+	mi := &file_proto_node_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -523,26 +678,36 @@ func (x *BalanceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BalanceRequest.ProtoReflect.Descriptor instead.
 func (*BalanceRequest) Descriptor() ([]byte, []int) {
-	return file_proto_node_proto_rawDescGZIP(), []int{9}
+	return file_proto_node_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *BalanceRequest) GetName() string {
+func (x *BalanceRequest) GetAddress() string {
 	if x != nil {
-		return x.Name
+		return x.Address
 	}
 	return ""
 }
 
+func (x *BalanceRequest) GetProve() bool {
+	if x != nil {
+		return x.Prove
+	}
+	return false
+}
+
 type BalanceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Balance       string                 `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Nonce         uint64                 `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Header        *BlockHeader           `protobuf:"bytes,3,opt,name=header,proto3" json:"header,omitempty"`
+	Proof         *StateProof            `protobuf:"bytes,4,opt,name=proof,proto3" json:"proof,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BalanceResponse) Reset() {
 	*x = BalanceResponse{}
-	mi := &file_proto_node_proto_msgTypes[10]
+	mi := &file_proto_node_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -554,7 +719,7 @@ func (x *BalanceResponse) String() string {
 func (*BalanceResponse) ProtoMessage() {}
 
 func (x *BalanceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_node_proto_msgTypes[10]
+	mi := &file_proto_node_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -567,7 +732,7 @@ func (x *BalanceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BalanceResponse.ProtoReflect.Descriptor instead.
 func (*BalanceResponse) Descriptor() ([]byte, []int) {
-	return file_proto_node_proto_rawDescGZIP(), []int{10}
+	return file_proto_node_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *BalanceResponse) GetBalance() string {
@@ -577,29 +742,51 @@ func (x *BalanceResponse) GetBalance() string {
 	return ""
 }
 
-type PriorityRequest struct {
+func (x *BalanceResponse) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *BalanceResponse) GetHeader() *BlockHeader {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *BalanceResponse) GetProof() *StateProof {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type StateProof struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	NodeId        string                 `protobuf:"bytes,1,opt,name=nodeId,proto3" json:"nodeId,omitempty"`
-	Priority      int32                  `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"`
+	Account       []byte                 `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Bitmap        string                 `protobuf:"bytes,2,opt,name=bitmap,proto3" json:"bitmap,omitempty"`
+	Siblings      []string               `protobuf:"bytes,3,rep,name=siblings,proto3" json:"siblings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PriorityRequest) Reset() {
-	*x = PriorityRequest{}
-	mi := &file_proto_node_proto_msgTypes[11]
+func (x *StateProof) Reset() {
+	*x = StateProof{}
+	mi := &file_proto_node_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PriorityRequest) String() string {
+func (x *StateProof) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PriorityRequest) ProtoMessage() {}
+func (*StateProof) ProtoMessage() {}
 
-func (x *PriorityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_node_proto_msgTypes[11]
+func (x *StateProof) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -610,48 +797,118 @@ func (x *PriorityRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PriorityRequest.ProtoReflect.Descriptor instead.
-func (*PriorityRequest) Descriptor() ([]byte, []int) {
-	return file_proto_node_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use StateProof.ProtoReflect.Descriptor instead.
+func (*StateProof) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *PriorityRequest) GetNodeId() string {
+func (x *StateProof) GetAccount() []byte {
 	if x != nil {
-		return x.NodeId
+		return x.Account
+	}
+	return nil
+}
+
+func (x *StateProof) GetBitmap() string {
+	if x != nil {
+		return x.Bitmap
 	}
 	return ""
 }
 
-func (x *PriorityRequest) GetPriority() int32 {
+func (x *StateProof) GetSiblings() []string {
 	if x != nil {
-		return x.Priority
+		return x.Siblings
+	}
+	return nil
+}
+
+type AccountHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountHistoryRequest) Reset() {
+	*x = AccountHistoryRequest{}
+	mi := &file_proto_node_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountHistoryRequest) ProtoMessage() {}
+
+func (x *AccountHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountHistoryRequest.ProtoReflect.Descriptor instead.
+func (*AccountHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AccountHistoryRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *AccountHistoryRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
 	}
 	return 0
 }
 
-type PriorityResponse struct {
+func (x *AccountHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type HistoryEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LeaderId      string                 `protobuf:"bytes,1,opt,name=leaderId,proto3" json:"leaderId,omitempty"`
-	Acknowledged  bool                   `protobuf:"varint,2,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	Height        int64                  `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Index         int32                  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	From          string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Transaction   *Transaction           `protobuf:"bytes,5,opt,name=transaction,proto3" json:"transaction,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PriorityResponse) Reset() {
-	*x = PriorityResponse{}
-	mi := &file_proto_node_proto_msgTypes[12]
+func (x *HistoryEntry) Reset() {
+	*x = HistoryEntry{}
+	mi := &file_proto_node_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PriorityResponse) String() string {
+func (x *HistoryEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PriorityResponse) ProtoMessage() {}
+func (*HistoryEntry) ProtoMessage() {}
 
-func (x *PriorityResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_node_proto_msgTypes[12]
+func (x *HistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -662,41 +919,790 @@ func (x *PriorityResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PriorityResponse.ProtoReflect.Descriptor instead.
-func (*PriorityResponse) Descriptor() ([]byte, []int) {
-	return file_proto_node_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use HistoryEntry.ProtoReflect.Descriptor instead.
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *PriorityResponse) GetLeaderId() string {
+func (x *HistoryEntry) GetHeight() int64 {
 	if x != nil {
-		return x.LeaderId
+		return x.Height
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetFrom() string {
+	if x != nil {
+		return x.From
 	}
 	return ""
 }
 
-func (x *PriorityResponse) GetAcknowledged() bool {
+func (x *HistoryEntry) GetTo() string {
 	if x != nil {
-		return x.Acknowledged
+		return x.To
 	}
-	return false
+	return ""
 }
 
-var File_proto_node_proto protoreflect.FileDescriptor
+func (x *HistoryEntry) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type AccountHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*HistoryEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountHistoryResponse) Reset() {
+	*x = AccountHistoryResponse{}
+	mi := &file_proto_node_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountHistoryResponse) ProtoMessage() {}
+
+func (x *AccountHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountHistoryResponse.ProtoReflect.Descriptor instead.
+func (*AccountHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AccountHistoryResponse) GetEntries() []*HistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *AccountHistoryResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type BlockHeader struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MerkleRoot       string                 `protobuf:"bytes,1,opt,name=merkleRoot,proto3" json:"merkleRoot,omitempty"`
+	PrevBlockHash    string                 `protobuf:"bytes,2,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
+	CurrentBlockHash string                 `protobuf:"bytes,3,opt,name=currentBlockHash,proto3" json:"currentBlockHash,omitempty"`
+	Height           int64                  `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	StateRoot        string                 `protobuf:"bytes,5,opt,name=stateRoot,proto3" json:"stateRoot,omitempty"`
+	Version          int32                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BlockHeader) Reset() {
+	*x = BlockHeader{}
+	mi := &file_proto_node_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockHeader) ProtoMessage() {}
+
+func (x *BlockHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockHeader.ProtoReflect.Descriptor instead.
+func (*BlockHeader) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BlockHeader) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetPrevBlockHash() string {
+	if x != nil {
+		return x.PrevBlockHash
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetCurrentBlockHash() string {
+	if x != nil {
+		return x.CurrentBlockHash
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetStateRoot() string {
+	if x != nil {
+		return x.StateRoot
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type TxProofRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxHash        string                 `protobuf:"bytes,1,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxProofRequest) Reset() {
+	*x = TxProofRequest{}
+	mi := &file_proto_node_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxProofRequest) ProtoMessage() {}
+
+func (x *TxProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxProofRequest.ProtoReflect.Descriptor instead.
+func (*TxProofRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TxProofRequest) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+type TxProofResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Header        *BlockHeader           `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Index         int32                  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Siblings      []string               `protobuf:"bytes,3,rep,name=siblings,proto3" json:"siblings,omitempty"`
+	Transaction   *Transaction           `protobuf:"bytes,4,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	TreeSize      int32                  `protobuf:"varint,5,opt,name=treeSize,proto3" json:"treeSize,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxProofResponse) Reset() {
+	*x = TxProofResponse{}
+	mi := &file_proto_node_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxProofResponse) ProtoMessage() {}
+
+func (x *TxProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxProofResponse.ProtoReflect.Descriptor instead.
+func (*TxProofResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TxProofResponse) GetHeader() *BlockHeader {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *TxProofResponse) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *TxProofResponse) GetSiblings() []string {
+	if x != nil {
+		return x.Siblings
+	}
+	return nil
+}
+
+func (x *TxProofResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *TxProofResponse) GetTreeSize() int32 {
+	if x != nil {
+		return x.TreeSize
+	}
+	return 0
+}
+
+type SnapshotManifest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Height        int64                  `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	BlockHash     string                 `protobuf:"bytes,2,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	StateRoot     string                 `protobuf:"bytes,3,opt,name=stateRoot,proto3" json:"stateRoot,omitempty"`
+	Chunks        []string               `protobuf:"bytes,4,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotManifest) Reset() {
+	*x = SnapshotManifest{}
+	mi := &file_proto_node_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotManifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotManifest) ProtoMessage() {}
+
+func (x *SnapshotManifest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotManifest.ProtoReflect.Descriptor instead.
+func (*SnapshotManifest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SnapshotManifest) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *SnapshotManifest) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *SnapshotManifest) GetStateRoot() string {
+	if x != nil {
+		return x.StateRoot
+	}
+	return ""
+}
+
+func (x *SnapshotManifest) GetChunks() []string {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+type SnapshotList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshots     []*SnapshotManifest    `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotList) Reset() {
+	*x = SnapshotList{}
+	mi := &file_proto_node_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotList) ProtoMessage() {}
+
+func (x *SnapshotList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotList.ProtoReflect.Descriptor instead.
+func (*SnapshotList) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SnapshotList) GetSnapshots() []*SnapshotManifest {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+type SnapshotChunkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Height        int64                  `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Index         int32                  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotChunkRequest) Reset() {
+	*x = SnapshotChunkRequest{}
+	mi := &file_proto_node_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotChunkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotChunkRequest) ProtoMessage() {}
+
+func (x *SnapshotChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotChunkRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotChunkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SnapshotChunkRequest) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *SnapshotChunkRequest) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+type SnapshotChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotChunk) Reset() {
+	*x = SnapshotChunk{}
+	mi := &file_proto_node_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotChunk) ProtoMessage() {}
+
+func (x *SnapshotChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotChunk.ProtoReflect.Descriptor instead.
+func (*SnapshotChunk) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SnapshotChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type RetainedRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HeadersFrom   int64                  `protobuf:"varint,1,opt,name=headersFrom,proto3" json:"headersFrom,omitempty"`
+	BodiesFrom    int64                  `protobuf:"varint,2,opt,name=bodiesFrom,proto3" json:"bodiesFrom,omitempty"`
+	Latest        int64                  `protobuf:"varint,3,opt,name=latest,proto3" json:"latest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetainedRange) Reset() {
+	*x = RetainedRange{}
+	mi := &file_proto_node_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetainedRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetainedRange) ProtoMessage() {}
+
+func (x *RetainedRange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetainedRange.ProtoReflect.Descriptor instead.
+func (*RetainedRange) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RetainedRange) GetHeadersFrom() int64 {
+	if x != nil {
+		return x.HeadersFrom
+	}
+	return 0
+}
+
+func (x *RetainedRange) GetBodiesFrom() int64 {
+	if x != nil {
+		return x.BodiesFrom
+	}
+	return 0
+}
+
+func (x *RetainedRange) GetLatest() int64 {
+	if x != nil {
+		return x.Latest
+	}
+	return 0
+}
+
+type PriorityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=nodeId,proto3" json:"nodeId,omitempty"`
+	Priority      int32                  `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriorityRequest) Reset() {
+	*x = PriorityRequest{}
+	mi := &file_proto_node_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriorityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriorityRequest) ProtoMessage() {}
+
+func (x *PriorityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriorityRequest.ProtoReflect.Descriptor instead.
+func (*PriorityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PriorityRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *PriorityRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type PriorityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LeaderId      string                 `protobuf:"bytes,1,opt,name=leaderId,proto3" json:"leaderId,omitempty"`
+	Acknowledged  bool                   `protobuf:"varint,2,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriorityResponse) Reset() {
+	*x = PriorityResponse{}
+	mi := &file_proto_node_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriorityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriorityResponse) ProtoMessage() {}
+
+func (x *PriorityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriorityResponse.ProtoReflect.Descriptor instead.
+func (*PriorityResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PriorityResponse) GetLeaderId() string {
+	if x != nil {
+		return x.LeaderId
+	}
+	return ""
+}
+
+func (x *PriorityResponse) GetAcknowledged() bool {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return false
+}
+
+type TxParamsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxParamsRequest) Reset() {
+	*x = TxParamsRequest{}
+	mi := &file_proto_node_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxParamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxParamsRequest) ProtoMessage() {}
+
+func (x *TxParamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxParamsRequest.ProtoReflect.Descriptor instead.
+func (*TxParamsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TxParamsRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type TxParams struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nonce         uint64                 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	MinFee        float64                `protobuf:"fixed64,2,opt,name=minFee,proto3" json:"minFee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxParams) Reset() {
+	*x = TxParams{}
+	mi := &file_proto_node_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxParams) ProtoMessage() {}
+
+func (x *TxParams) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxParams.ProtoReflect.Descriptor instead.
+func (*TxParams) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TxParams) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *TxParams) GetMinFee() float64 {
+	if x != nil {
+		return x.MinFee
+	}
+	return 0
+}
+
+var File_proto_node_proto protoreflect.FileDescriptor
 
 const file_proto_node_proto_rawDesc = "" +
 	"\n" +
-	"\x10proto/node.proto\x12\x02pb\"\x95\x01\n" +
+	"\x10proto/node.proto\x12\x02pb\"\xf1\x01\n" +
 	"\vTransaction\x12\x16\n" +
 	"\x06sender\x18\x01 \x01(\fR\x06sender\x12\x1a\n" +
 	"\breceiver\x18\x02 \x01(\fR\breceiver\x12\x16\n" +
 	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x1c\n" +
 	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x1c\n" +
-	"\tsignature\x18\x05 \x01(\fR\tsignature\">\n" +
+	"\tsignature\x18\x05 \x01(\fR\tsignature\x12\x18\n" +
+	"\akeyType\x18\x06 \x01(\rR\akeyType\x12\x18\n" +
+	"\aversion\x18\a \x01(\x05R\aversion\x12\x14\n" +
+	"\x05nonce\x18\b \x01(\x04R\x05nonce\x12\x10\n" +
+	"\x03fee\x18\t \x01(\x01R\x03fee\">\n" +
 	"\n" +
 	"TxResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\"\a\n" +
-	"\x05Empty\"\xc6\x01\n" +
+	"\x05Empty\"\xfe\x01\n" +
 	"\x05Block\x123\n" +
 	"\ftransactions\x18\x01 \x03(\v2\x0f.pb.TransactionR\ftransactions\x12\x1e\n" +
 	"\n" +
@@ -704,39 +1710,120 @@ const file_proto_node_proto_rawDesc = "" +
 	"merkleRoot\x12$\n" +
 	"\rprevBlockHash\x18\x03 \x01(\tR\rprevBlockHash\x12*\n" +
 	"\x10currentBlockHash\x18\x04 \x01(\tR\x10currentBlockHash\x12\x16\n" +
-	"\x06height\x18\x05 \x01(\x03R\x06height\".\n" +
+	"\x06height\x18\x05 \x01(\x03R\x06height\x12\x1c\n" +
+	"\tstateRoot\x18\x06 \x01(\tR\tstateRoot\x12\x18\n" +
+	"\aversion\x18\a \x01(\x05R\aversion\"\x98\x01\n" +
 	"\vVoteRequest\x12\x1f\n" +
-	"\x05block\x18\x01 \x01(\v2\t.pb.BlockR\x05block\"B\n" +
+	"\x05block\x18\x01 \x01(\v2\t.pb.BlockR\x05block\x12 \n" +
+	"\vproposerKey\x18\x02 \x01(\fR\vproposerKey\x12(\n" +
+	"\x0fproposerKeyType\x18\x03 \x01(\rR\x0fproposerKeyType\x12\x1c\n" +
+	"\tsignature\x18\x04 \x01(\fR\tsignature\"\xa0\x01\n" +
 	"\fVoteResponse\x12\x16\n" +
 	"\x06nodeId\x18\x01 \x01(\tR\x06nodeId\x12\x1a\n" +
-	"\bapproved\x18\x02 \x01(\bR\bapproved\"\"\n" +
+	"\bapproved\x18\x02 \x01(\bR\bapproved\x12\x1a\n" +
+	"\bvoterKey\x18\x03 \x01(\fR\bvoterKey\x12\"\n" +
+	"\fvoterKeyType\x18\x04 \x01(\rR\fvoterKeyType\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\fR\tsignature\"d\n" +
+	"\rCommitRequest\x12+\n" +
+	"\bproposal\x18\x01 \x01(\v2\x0f.pb.VoteRequestR\bproposal\x12&\n" +
+	"\x05votes\x18\x02 \x03(\v2\x10.pb.VoteResponseR\x05votes\"\"\n" +
 	"\fBlockRequest\x12\x12\n" +
 	"\x04hash\x18\x01 \x01(\tR\x04hash\"0\n" +
 	"\rBlockResponse\x12\x1f\n" +
 	"\x05block\x18\x01 \x01(\v2\t.pb.BlockR\x05block\"'\n" +
 	"\rHeightRequest\x12\x16\n" +
-	"\x06height\x18\x01 \x01(\x03R\x06height\"$\n" +
-	"\x0eBalanceRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"+\n" +
+	"\x06height\x18\x01 \x01(\x03R\x06height\"@\n" +
+	"\x0eBalanceRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05prove\x18\x02 \x01(\bR\x05prove\"\x90\x01\n" +
 	"\x0fBalanceResponse\x12\x18\n" +
-	"\abalance\x18\x01 \x01(\tR\abalance\"E\n" +
+	"\abalance\x18\x01 \x01(\tR\abalance\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\x04R\x05nonce\x12'\n" +
+	"\x06header\x18\x03 \x01(\v2\x0f.pb.BlockHeaderR\x06header\x12$\n" +
+	"\x05proof\x18\x04 \x01(\v2\x0e.pb.StateProofR\x05proof\"Z\n" +
+	"\n" +
+	"StateProof\x12\x18\n" +
+	"\aaccount\x18\x01 \x01(\fR\aaccount\x12\x16\n" +
+	"\x06bitmap\x18\x02 \x01(\tR\x06bitmap\x12\x1a\n" +
+	"\bsiblings\x18\x03 \x03(\tR\bsiblings\"_\n" +
+	"\x15AccountHistoryRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\x93\x01\n" +
+	"\fHistoryEntry\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x03R\x06height\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x121\n" +
+	"\vtransaction\x18\x05 \x01(\v2\x0f.pb.TransactionR\vtransaction\"Z\n" +
+	"\x16AccountHistoryResponse\x12*\n" +
+	"\aentries\x18\x01 \x03(\v2\x10.pb.HistoryEntryR\aentries\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xcf\x01\n" +
+	"\vBlockHeader\x12\x1e\n" +
+	"\n" +
+	"merkleRoot\x18\x01 \x01(\tR\n" +
+	"merkleRoot\x12$\n" +
+	"\rprevBlockHash\x18\x02 \x01(\tR\rprevBlockHash\x12*\n" +
+	"\x10currentBlockHash\x18\x03 \x01(\tR\x10currentBlockHash\x12\x16\n" +
+	"\x06height\x18\x04 \x01(\x03R\x06height\x12\x1c\n" +
+	"\tstateRoot\x18\x05 \x01(\tR\tstateRoot\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x05R\aversion\"(\n" +
+	"\x0eTxProofRequest\x12\x16\n" +
+	"\x06txHash\x18\x01 \x01(\tR\x06txHash\"\xbb\x01\n" +
+	"\x0fTxProofResponse\x12'\n" +
+	"\x06header\x18\x01 \x01(\v2\x0f.pb.BlockHeaderR\x06header\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x05R\x05index\x12\x1a\n" +
+	"\bsiblings\x18\x03 \x03(\tR\bsiblings\x121\n" +
+	"\vtransaction\x18\x04 \x01(\v2\x0f.pb.TransactionR\vtransaction\x12\x1a\n" +
+	"\btreeSize\x18\x05 \x01(\x05R\btreeSize\"~\n" +
+	"\x10SnapshotManifest\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x03R\x06height\x12\x1c\n" +
+	"\tblockHash\x18\x02 \x01(\tR\tblockHash\x12\x1c\n" +
+	"\tstateRoot\x18\x03 \x01(\tR\tstateRoot\x12\x16\n" +
+	"\x06chunks\x18\x04 \x03(\tR\x06chunks\"B\n" +
+	"\fSnapshotList\x122\n" +
+	"\tsnapshots\x18\x01 \x03(\v2\x14.pb.SnapshotManifestR\tsnapshots\"D\n" +
+	"\x14SnapshotChunkRequest\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x03R\x06height\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x05R\x05index\"#\n" +
+	"\rSnapshotChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"i\n" +
+	"\rRetainedRange\x12 \n" +
+	"\vheadersFrom\x18\x01 \x01(\x03R\vheadersFrom\x12\x1e\n" +
+	"\n" +
+	"bodiesFrom\x18\x02 \x01(\x03R\n" +
+	"bodiesFrom\x12\x16\n" +
+	"\x06latest\x18\x03 \x01(\x03R\x06latest\"E\n" +
 	"\x0fPriorityRequest\x12\x16\n" +
 	"\x06nodeId\x18\x01 \x01(\tR\x06nodeId\x12\x1a\n" +
 	"\bpriority\x18\x02 \x01(\x05R\bpriority\"R\n" +
 	"\x10PriorityResponse\x12\x1a\n" +
 	"\bleaderId\x18\x01 \x01(\tR\bleaderId\x12\"\n" +
-	"\facknowledged\x18\x02 \x01(\bR\facknowledged2\xd2\x03\n" +
+	"\facknowledged\x18\x02 \x01(\bR\facknowledged\"+\n" +
+	"\x0fTxParamsRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\"8\n" +
+	"\bTxParams\x12\x14\n" +
+	"\x05nonce\x18\x01 \x01(\x04R\x05nonce\x12\x16\n" +
+	"\x06minFee\x18\x02 \x01(\x01R\x06minFee2\xe9\x06\n" +
 	"\vNodeService\x122\n" +
 	"\x0fSendTransaction\x12\x0f.pb.Transaction\x1a\x0e.pb.TxResponse\x12!\n" +
 	"\x04Ping\x12\t.pb.Empty\x1a\x0e.pb.TxResponse\x121\n" +
-	"\fProposeBlock\x12\x0f.pb.VoteRequest\x1a\x10.pb.VoteResponse\x12(\n" +
-	"\vCommitBlock\x12\t.pb.Block\x1a\x0e.pb.TxResponse\x12.\n" +
+	"\fProposeBlock\x12\x0f.pb.VoteRequest\x1a\x10.pb.VoteResponse\x120\n" +
+	"\vCommitBlock\x12\x11.pb.CommitRequest\x1a\x0e.pb.TxResponse\x12.\n" +
 	"\x0eGetLatestBlock\x12\t.pb.Empty\x1a\x11.pb.BlockResponse\x12/\n" +
 	"\bGetBlock\x12\x10.pb.BlockRequest\x1a\x11.pb.BlockResponse\x128\n" +
 	"\x10GetBlockByHeight\x12\x11.pb.HeightRequest\x1a\x11.pb.BlockResponse\x125\n" +
 	"\n" +
 	"GetBalance\x12\x12.pb.BalanceRequest\x1a\x13.pb.BalanceResponse\x12=\n" +
-	"\x10ExchangePriority\x12\x13.pb.PriorityRequest\x1a\x14.pb.PriorityResponseB\fZ\n" +
+	"\x10ExchangePriority\x12\x13.pb.PriorityRequest\x1a\x14.pb.PriorityResponse\x12J\n" +
+	"\x11GetAccountHistory\x12\x19.pb.AccountHistoryRequest\x1a\x1a.pb.AccountHistoryResponse\x125\n" +
+	"\n" +
+	"GetTxProof\x12\x12.pb.TxProofRequest\x1a\x13.pb.TxProofResponse\x12,\n" +
+	"\rListSnapshots\x12\t.pb.Empty\x1a\x10.pb.SnapshotList\x12?\n" +
+	"\x10GetSnapshotChunk\x12\x18.pb.SnapshotChunkRequest\x1a\x11.pb.SnapshotChunk\x120\n" +
+	"\x10GetRetainedRange\x12\t.pb.Empty\x1a\x11.pb.RetainedRange\x127\n" +
+	"\x11GetHeaderByHeight\x12\x11.pb.HeightRequest\x1a\x0f.pb.BlockHeader\x120\n" +
+	"\vGetTxParams\x12\x13.pb.TxParamsRequest\x1a\f.pb.TxParamsB\fZ\n" +
 	"pkg/p2p/pbb\x06proto3"
 
 var (
@@ -751,49 +1838,87 @@ func file_proto_node_proto_rawDescGZIP() []byte {
 	return file_proto_node_proto_rawDescData
 }
 
-var file_proto_node_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_node_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_proto_node_proto_goTypes = []any{
-	(*Transaction)(nil),      // 0: pb.Transaction
-	(*TxResponse)(nil),       // 1: pb.TxResponse
-	(*Empty)(nil),            // 2: pb.Empty
-	(*Block)(nil),            // 3: pb.Block
-	(*VoteRequest)(nil),      // 4: pb.VoteRequest
-	(*VoteResponse)(nil),     // 5: pb.VoteResponse
-	(*BlockRequest)(nil),     // 6: pb.BlockRequest
-	(*BlockResponse)(nil),    // 7: pb.BlockResponse
-	(*HeightRequest)(nil),    // 8: pb.HeightRequest
-	(*BalanceRequest)(nil),   // 9: pb.BalanceRequest
-	(*BalanceResponse)(nil),  // 10: pb.BalanceResponse
-	(*PriorityRequest)(nil),  // 11: pb.PriorityRequest
-	(*PriorityResponse)(nil), // 12: pb.PriorityResponse
+	(*Transaction)(nil),            // 0: pb.Transaction
+	(*TxResponse)(nil),             // 1: pb.TxResponse
+	(*Empty)(nil),                  // 2: pb.Empty
+	(*Block)(nil),                  // 3: pb.Block
+	(*VoteRequest)(nil),            // 4: pb.VoteRequest
+	(*VoteResponse)(nil),           // 5: pb.VoteResponse
+	(*CommitRequest)(nil),          // 6: pb.CommitRequest
+	(*BlockRequest)(nil),           // 7: pb.BlockRequest
+	(*BlockResponse)(nil),          // 8: pb.BlockResponse
+	(*HeightRequest)(nil),          // 9: pb.HeightRequest
+	(*BalanceRequest)(nil),         // 10: pb.BalanceRequest
+	(*BalanceResponse)(nil),        // 11: pb.BalanceResponse
+	(*StateProof)(nil),             // 12: pb.StateProof
+	(*AccountHistoryRequest)(nil),  // 13: pb.AccountHistoryRequest
+	(*HistoryEntry)(nil),           // 14: pb.HistoryEntry
+	(*AccountHistoryResponse)(nil), // 15: pb.AccountHistoryResponse
+	(*BlockHeader)(nil),            // 16: pb.BlockHeader
+	(*TxProofRequest)(nil),         // 17: pb.TxProofRequest
+	(*TxProofResponse)(nil),        // 18: pb.TxProofResponse
+	(*SnapshotManifest)(nil),       // 19: pb.SnapshotManifest
+	(*SnapshotList)(nil),           // 20: pb.SnapshotList
+	(*SnapshotChunkRequest)(nil),   // 21: pb.SnapshotChunkRequest
+	(*SnapshotChunk)(nil),          // 22: pb.SnapshotChunk
+	(*RetainedRange)(nil),          // 23: pb.RetainedRange
+	(*PriorityRequest)(nil),        // 24: pb.PriorityRequest
+	(*PriorityResponse)(nil),       // 25: pb.PriorityResponse
+	(*TxParamsRequest)(nil),        // 26: pb.TxParamsRequest
+	(*TxParams)(nil),               // 27: pb.TxParams
 }
 var file_proto_node_proto_depIdxs = []int32{
 	0,  // 0: pb.Block.transactions:type_name -> pb.Transaction
 	3,  // 1: pb.VoteRequest.block:type_name -> pb.Block
-	3,  // 2: pb.BlockResponse.block:type_name -> pb.Block
-	0,  // 3: pb.NodeService.SendTransaction:input_type -> pb.Transaction
-	2,  // 4: pb.NodeService.Ping:input_type -> pb.Empty
-	4,  // 5: pb.NodeService.ProposeBlock:input_type -> pb.VoteRequest
-	3,  // 6: pb.NodeService.CommitBlock:input_type -> pb.Block
-	2,  // 7: pb.NodeService.GetLatestBlock:input_type -> pb.Empty
-	6,  // 8: pb.NodeService.GetBlock:input_type -> pb.BlockRequest
-	8,  // 9: pb.NodeService.GetBlockByHeight:input_type -> pb.HeightRequest
-	9,  // 10: pb.NodeService.GetBalance:input_type -> pb.BalanceRequest
-	11, // 11: pb.NodeService.ExchangePriority:input_type -> pb.PriorityRequest
-	1,  // 12: pb.NodeService.SendTransaction:output_type -> pb.TxResponse
-	1,  // 13: pb.NodeService.Ping:output_type -> pb.TxResponse
-	5,  // 14: pb.NodeService.ProposeBlock:output_type -> pb.VoteResponse
-	1,  // 15: pb.NodeService.CommitBlock:output_type -> pb.TxResponse
-	7,  // 16: pb.NodeService.GetLatestBlock:output_type -> pb.BlockResponse
-	7,  // 17: pb.NodeService.GetBlock:output_type -> pb.BlockResponse
-	7,  // 18: pb.NodeService.GetBlockByHeight:output_type -> pb.BlockResponse
-	10, // 19: pb.NodeService.GetBalance:output_type -> pb.BalanceResponse
-	12, // 20: pb.NodeService.ExchangePriority:output_type -> pb.PriorityResponse
-	12, // [12:21] is the sub-list for method output_type
-	3,  // [3:12] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	4,  // 2: pb.CommitRequest.proposal:type_name -> pb.VoteRequest
+	5,  // 3: pb.CommitRequest.votes:type_name -> pb.VoteResponse
+	3,  // 4: pb.BlockResponse.block:type_name -> pb.Block
+	16, // 5: pb.BalanceResponse.header:type_name -> pb.BlockHeader
+	12, // 6: pb.BalanceResponse.proof:type_name -> pb.StateProof
+	0,  // 7: pb.HistoryEntry.transaction:type_name -> pb.Transaction
+	14, // 8: pb.AccountHistoryResponse.entries:type_name -> pb.HistoryEntry
+	16, // 9: pb.TxProofResponse.header:type_name -> pb.BlockHeader
+	0,  // 10: pb.TxProofResponse.transaction:type_name -> pb.Transaction
+	19, // 11: pb.SnapshotList.snapshots:type_name -> pb.SnapshotManifest
+	0,  // 12: pb.NodeService.SendTransaction:input_type -> pb.Transaction
+	2,  // 13: pb.NodeService.Ping:input_type -> pb.Empty
+	4,  // 14: pb.NodeService.ProposeBlock:input_type -> pb.VoteRequest
+	6,  // 15: pb.NodeService.CommitBlock:input_type -> pb.CommitRequest
+	2,  // 16: pb.NodeService.GetLatestBlock:input_type -> pb.Empty
+	7,  // 17: pb.NodeService.GetBlock:input_type -> pb.BlockRequest
+	9,  // 18: pb.NodeService.GetBlockByHeight:input_type -> pb.HeightRequest
+	10, // 19: pb.NodeService.GetBalance:input_type -> pb.BalanceRequest
+	24, // 20: pb.NodeService.ExchangePriority:input_type -> pb.PriorityRequest
+	13, // 21: pb.NodeService.GetAccountHistory:input_type -> pb.AccountHistoryRequest
+	17, // 22: pb.NodeService.GetTxProof:input_type -> pb.TxProofRequest
+	2,  // 23: pb.NodeService.ListSnapshots:input_type -> pb.Empty
+	21, // 24: pb.NodeService.GetSnapshotChunk:input_type -> pb.SnapshotChunkRequest
+	2,  // 25: pb.NodeService.GetRetainedRange:input_type -> pb.Empty
+	9,  // 26: pb.NodeService.GetHeaderByHeight:input_type -> pb.HeightRequest
+	26, // 27: pb.NodeService.GetTxParams:input_type -> pb.TxParamsRequest
+	1,  // 28: pb.NodeService.SendTransaction:output_type -> pb.TxResponse
+	1,  // 29: pb.NodeService.Ping:output_type -> pb.TxResponse
+	5,  // 30: pb.NodeService.ProposeBlock:output_type -> pb.VoteResponse
+	1,  // 31: pb.NodeService.CommitBlock:output_type -> pb.TxResponse
+	8,  // 32: pb.NodeService.GetLatestBlock:output_type -> pb.BlockResponse
+	8,  // 33: pb.NodeService.GetBlock:output_type -> pb.BlockResponse
+	8,  // 34: pb.NodeService.GetBlockByHeight:output_type -> pb.BlockResponse
+	11, // 35: pb.NodeService.GetBalance:output_type -> pb.BalanceResponse
+	25, // 36: pb.NodeService.ExchangePriority:output_type -> pb.PriorityResponse
+	15, // 37: pb.NodeService.GetAccountHistory:output_type -> pb.AccountHistoryResponse
+	18, // 38: pb.NodeService.GetTxProof:output_type -> pb.TxProofResponse
+	20, // 39: pb.NodeService.ListSnapshots:output_type -> pb.SnapshotList
+	22, // 40: pb.NodeService.GetSnapshotChunk:output_type -> pb.SnapshotChunk
+	23, // 41: pb.NodeService.GetRetainedRange:output_type -> pb.RetainedRange
+	16, // 42: pb.NodeService.GetHeaderByHeight:output_type -> pb.BlockHeader
+	27, // 43: pb.NodeService.GetTxParams:output_type -> pb.TxParams
+	28, // [28:44] is the sub-list for method output_type
+	12, // [12:28] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_proto_node_proto_init() }
@@ -807,7 +1932,7 @@ func file_proto_node_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_node_proto_rawDesc), len(file_proto_node_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,15 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	NodeService_SendTransaction_FullMethodName  = "/pb.NodeService/SendTransaction"
-	NodeService_Ping_FullMethodName             = "/pb.NodeService/Ping"
-	NodeService_ProposeBlock_FullMethodName     = "/pb.NodeService/ProposeBlock"
-	NodeService_CommitBlock_FullMethodName      = "/pb.NodeService/CommitBlock"
-	NodeService_GetLatestBlock_FullMethodName   = "/pb.NodeService/GetLatestBlock"
-	NodeService_GetBlock_FullMethodName         = "/pb.NodeService/GetBlock"
-	NodeService_GetBlockByHeight_FullMethodName = "/pb.NodeService/GetBlockByHeight"
-	NodeService_GetBalance_FullMethodName       = "/pb.NodeService/GetBalance"
-	NodeService_ExchangePriority_FullMethodName = "/pb.NodeService/ExchangePriority"
+	NodeService_SendTransaction_FullMethodName   = "/pb.NodeService/SendTransaction"
+	NodeService_Ping_FullMethodName              = "/pb.NodeService/Ping"
+	NodeService_ProposeBlock_FullMethodName      = "/pb.NodeService/ProposeBlock"
+	NodeService_CommitBlock_FullMethodName       = "/pb.NodeService/CommitBlock"
+	NodeService_GetLatestBlock_FullMethodName    = "/pb.NodeService/GetLatestBlock"
+	NodeService_GetBlock_FullMethodName          = "/pb.NodeService/GetBlock"
+	NodeService_GetBlockByHeight_FullMethodName  = "/pb.NodeService/GetBlockByHeight"
+	NodeService_GetBalance_FullMethodName        = "/pb.NodeService/GetBalance"
+	NodeService_ExchangePriority_FullMethodName  = "/pb.NodeService/ExchangePriority"
+	NodeService_GetAccountHistory_FullMethodName = "/pb.NodeService/GetAccountHistory"
+	NodeService_GetTxProof_FullMethodName        = "/pb.NodeService/GetTxProof"
+	NodeService_ListSnapshots_FullMethodName     = "/pb.NodeService/ListSnapshots"
+	NodeService_GetSnapshotChunk_FullMethodName  = "/pb.NodeService/GetSnapshotChunk"
+	NodeService_GetRetainedRange_FullMethodName  = "/pb.NodeService/GetRetainedRange"
+	NodeService_GetHeaderByHeight_FullMethodName = "/pb.NodeService/GetHeaderByHeight"
+	NodeService_GetTxParams_FullMethodName       = "/pb.NodeService/GetTxParams"
 )
 
 // NodeServiceClient is the client API for NodeService service.
@@ -37,12 +44,19 @@ type NodeServiceClient interface {
 	SendTransaction(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*TxResponse, error)
 	Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TxResponse, error)
 	ProposeBlock(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*VoteResponse, error)
-	CommitBlock(ctx context.Context, in *Block, opts ...grpc.CallOption) (*TxResponse, error)
+	CommitBlock(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*TxResponse, error)
 	GetLatestBlock(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlockResponse, error)
 	GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockResponse, error)
 	GetBlockByHeight(ctx context.Context, in *HeightRequest, opts ...grpc.CallOption) (*BlockResponse, error)
 	GetBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
 	ExchangePriority(ctx context.Context, in *PriorityRequest, opts ...grpc.CallOption) (*PriorityResponse, error)
+	GetAccountHistory(ctx context.Context, in *AccountHistoryRequest, opts ...grpc.CallOption) (*AccountHistoryResponse, error)
+	GetTxProof(ctx context.Context, in *TxProofRequest, opts ...grpc.CallOption) (*TxProofResponse, error)
+	ListSnapshots(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SnapshotList, error)
+	GetSnapshotChunk(ctx context.Context, in *SnapshotChunkRequest, opts ...grpc.CallOption) (*SnapshotChunk, error)
+	GetRetainedRange(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RetainedRange, error)
+	GetHeaderByHeight(ctx context.Context, in *HeightRequest, opts ...grpc.CallOption) (*BlockHeader, error)
+	GetTxParams(ctx context.Context, in *TxParamsRequest, opts ...grpc.CallOption) (*TxParams, error)
 }
 
 type nodeServiceClient struct {
@@ -83,7 +97,7 @@ func (c *nodeServiceClient) ProposeBlock(ctx context.Context, in *VoteRequest, o
 	return out, nil
 }
 
-func (c *nodeServiceClient) CommitBlock(ctx context.Context, in *Block, opts ...grpc.CallOption) (*TxResponse, error) {
+func (c *nodeServiceClient) CommitBlock(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*TxResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TxResponse)
 	err := c.cc.Invoke(ctx, NodeService_CommitBlock_FullMethodName, in, out, cOpts...)
@@ -143,6 +157,76 @@ func (c *nodeServiceClient) ExchangePriority(ctx context.Context, in *PriorityRe
 	return out, nil
 }
 
+func (c *nodeServiceClient) GetAccountHistory(ctx context.Context, in *AccountHistoryRequest, opts ...grpc.CallOption) (*AccountHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountHistoryResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetAccountHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetTxProof(ctx context.Context, in *TxProofRequest, opts ...grpc.CallOption) (*TxProofResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TxProofResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetTxProof_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) ListSnapshots(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SnapshotList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SnapshotList)
+	err := c.cc.Invoke(ctx, NodeService_ListSnapshots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetSnapshotChunk(ctx context.Context, in *SnapshotChunkRequest, opts ...grpc.CallOption) (*SnapshotChunk, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SnapshotChunk)
+	err := c.cc.Invoke(ctx, NodeService_GetSnapshotChunk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetRetainedRange(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RetainedRange, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetainedRange)
+	err := c.cc.Invoke(ctx, NodeService_GetRetainedRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetHeaderByHeight(ctx context.Context, in *HeightRequest, opts ...grpc.CallOption) (*BlockHeader, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlockHeader)
+	err := c.cc.Invoke(ctx, NodeService_GetHeaderByHeight_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetTxParams(ctx context.Context, in *TxParamsRequest, opts ...grpc.CallOption) (*TxParams, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TxParams)
+	err := c.cc.Invoke(ctx, NodeService_GetTxParams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NodeServiceServer is the server API for NodeService service.
 // All implementations must embed UnimplementedNodeServiceServer
 // for forward compatibility.
@@ -150,12 +234,19 @@ type NodeServiceServer interface {
 	SendTransaction(context.Context, *Transaction) (*TxResponse, error)
 	Ping(context.Context, *Empty) (*TxResponse, error)
 	ProposeBlock(context.Context, *VoteRequest) (*VoteResponse, error)
-	CommitBlock(context.Context, *Block) (*TxResponse, error)
+	CommitBlock(context.Context, *CommitRequest) (*TxResponse, error)
 	GetLatestBlock(context.Context, *Empty) (*BlockResponse, error)
 	GetBlock(context.Context, *BlockRequest) (*BlockResponse, error)
 	GetBlockByHeight(context.Context, *HeightRequest) (*BlockResponse, error)
 	GetBalance(context.Context, *BalanceRequest) (*BalanceResponse, error)
 	ExchangePriority(context.Context, *PriorityRequest) (*PriorityResponse, error)
+	GetAccountHistory(context.Context, *AccountHistoryRequest) (*AccountHistoryResponse, error)
+	GetTxProof(context.Context, *TxProofRequest) (*TxProofResponse, error)
+	ListSnapshots(context.Context, *Empty) (*SnapshotList, error)
+	GetSnapshotChunk(context.Context, *SnapshotChunkRequest) (*SnapshotChunk, error)
+	GetRetainedRange(context.Context, *Empty) (*RetainedRange, error)
+	GetHeaderByHeight(context.Context, *HeightRequest) (*BlockHeader, error)
+	GetTxParams(context.Context, *TxParamsRequest) (*TxParams, error)
 	mustEmbedUnimplementedNodeServiceServer()
 }
 
@@ -175,7 +266,7 @@ func (UnimplementedNodeServiceServer) Ping(context.Context, *Empty) (*TxResponse
 func (UnimplementedNodeServiceServer) ProposeBlock(context.Context, *VoteRequest) (*VoteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ProposeBlock not implemented")
 }
-func (UnimplementedNodeServiceServer) CommitBlock(context.Context, *Block) (*TxResponse, error) {
+func (UnimplementedNodeServiceServer) CommitBlock(context.Context, *CommitRequest) (*TxResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CommitBlock not implemented")
 }
 func (UnimplementedNodeServiceServer) GetLatestBlock(context.Context, *Empty) (*BlockResponse, error) {
@@ -193,6 +284,27 @@ func (UnimplementedNodeServiceServer) GetBalance(context.Context, *BalanceReques
 func (UnimplementedNodeServiceServer) ExchangePriority(context.Context, *PriorityRequest) (*PriorityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExchangePriority not implemented")
 }
+func (UnimplementedNodeServiceServer) GetAccountHistory(context.Context, *AccountHistoryRequest) (*AccountHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountHistory not implemented")
+}
+func (UnimplementedNodeServiceServer) GetTxProof(context.Context, *TxProofRequest) (*TxProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTxProof not implemented")
+}
+func (UnimplementedNodeServiceServer) ListSnapshots(context.Context, *Empty) (*SnapshotList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSnapshots not implemented")
+}
+func (UnimplementedNodeServiceServer) GetSnapshotChunk(context.Context, *SnapshotChunkRequest) (*SnapshotChunk, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshotChunk not implemented")
+}
+func (UnimplementedNodeServiceServer) GetRetainedRange(context.Context, *Empty) (*RetainedRange, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetainedRange not implemented")
+}
+func (UnimplementedNodeServiceServer) GetHeaderByHeight(context.Context, *HeightRequest) (*BlockHeader, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeaderByHeight not implemented")
+}
+func (UnimplementedNodeServiceServer) GetTxParams(context.Context, *TxParamsRequest) (*TxParams, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTxParams not implemented")
+}
 func (UnimplementedNodeServiceServer) mustEmbedUnimplementedNodeServiceServer() {}
 func (UnimplementedNodeServiceServer) testEmbeddedByValue()                     {}
 
@@ -269,7 +381,7 @@ func _NodeService_ProposeBlock_Handler(srv interface{}, ctx context.Context, dec
 }
 
 func _NodeService_CommitBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Block)
+	in := new(CommitRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -281,7 +393,7 @@ func _NodeService_CommitBlock_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: NodeService_CommitBlock_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).CommitBlock(ctx, req.(*Block))
+		return srv.(NodeServiceServer).CommitBlock(ctx, req.(*CommitRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -376,6 +488,132 @@ func _NodeService_ExchangePriority_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_GetAccountHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetAccountHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetAccountHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetAccountHistory(ctx, req.(*AccountHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetTxProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetTxProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetTxProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetTxProof(ctx, req.(*TxProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_ListSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).ListSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_ListSnapshots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).ListSnapshots(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetSnapshotChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetSnapshotChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetSnapshotChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetSnapshotChunk(ctx, req.(*SnapshotChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetRetainedRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetRetainedRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetRetainedRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetRetainedRange(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetHeaderByHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetHeaderByHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetHeaderByHeight_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetHeaderByHeight(ctx, req.(*HeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetTxParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetTxParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetTxParams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetTxParams(ctx, req.(*TxParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NodeService_ServiceDesc is the grpc.ServiceDesc for NodeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -419,6 +657,34 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExchangePriority",
 			Handler:    _NodeService_ExchangePriority_Handler,
 		},
+		{
+			MethodName: "GetAccountHistory",
+			Handler:    _NodeService_GetAccountHistory_Handler,
+		},
+		{
+			MethodName: "GetTxProof",
+			Handler:    _NodeService_GetTxProof_Handler,
+		},
+		{
+			MethodName: "ListSnapshots",
+			Handler:    _NodeService_ListSnapshots_Handler,
+		},
+		{
+			MethodName: "GetSnapshotChunk",
+			Handler:    _NodeService_GetSnapshotChunk_Handler,
+		},
+		{
+			MethodName: "GetRetainedRange",
+			Handler:    _NodeService_GetRetainedRange_Handler,
+		},
+		{
+			MethodName: "GetHeaderByHeight",
+			Handler:    _NodeService_GetHeaderByHeight_Handler,
+		},
+		{
+			MethodName: "GetTxParams",
+			Handler:    _NodeService_GetTxParams_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/node.proto",
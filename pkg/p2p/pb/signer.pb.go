@@ -0,0 +1,298 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: proto/signer.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SignerKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignerKeyRequest) Reset() {
+	*x = SignerKeyRequest{}
+	mi := &file_proto_signer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignerKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignerKeyRequest) ProtoMessage() {}
+
+func (x *SignerKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_signer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignerKeyRequest.ProtoReflect.Descriptor instead.
+func (*SignerKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_signer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SignerKeyRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type SignerPublicKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyType       uint32                 `protobuf:"varint,1,opt,name=keyType,proto3" json:"keyType,omitempty"`
+	PublicKey     []byte                 `protobuf:"bytes,2,opt,name=publicKey,proto3" json:"publicKey,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignerPublicKey) Reset() {
+	*x = SignerPublicKey{}
+	mi := &file_proto_signer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignerPublicKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignerPublicKey) ProtoMessage() {}
+
+func (x *SignerPublicKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_signer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignerPublicKey.ProtoReflect.Descriptor instead.
+func (*SignerPublicKey) Descriptor() ([]byte, []int) {
+	return file_proto_signer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SignerPublicKey) GetKeyType() uint32 {
+	if x != nil {
+		return x.KeyType
+	}
+	return 0
+}
+
+func (x *SignerPublicKey) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+type SignRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Hash          []byte                 `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Recoverable   bool                   `protobuf:"varint,3,opt,name=recoverable,proto3" json:"recoverable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignRequest) Reset() {
+	*x = SignRequest{}
+	mi := &file_proto_signer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignRequest) ProtoMessage() {}
+
+func (x *SignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_signer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignRequest.ProtoReflect.Descriptor instead.
+func (*SignRequest) Descriptor() ([]byte, []int) {
+	return file_proto_signer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SignRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SignRequest) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *SignRequest) GetRecoverable() bool {
+	if x != nil {
+		return x.Recoverable
+	}
+	return false
+}
+
+type SignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signature     []byte                 `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignResponse) Reset() {
+	*x = SignResponse{}
+	mi := &file_proto_signer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignResponse) ProtoMessage() {}
+
+func (x *SignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_signer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignResponse.ProtoReflect.Descriptor instead.
+func (*SignResponse) Descriptor() ([]byte, []int) {
+	return file_proto_signer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SignResponse) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+var File_proto_signer_proto protoreflect.FileDescriptor
+
+const file_proto_signer_proto_rawDesc = "" +
+	"\n" +
+	"\x12proto/signer.proto\x12\x02pb\"$\n" +
+	"\x10SignerKeyRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"I\n" +
+	"\x0fSignerPublicKey\x12\x18\n" +
+	"\akeyType\x18\x01 \x01(\rR\akeyType\x12\x1c\n" +
+	"\tpublicKey\x18\x02 \x01(\fR\tpublicKey\"U\n" +
+	"\vSignRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04hash\x18\x02 \x01(\fR\x04hash\x12 \n" +
+	"\vrecoverable\x18\x03 \x01(\bR\vrecoverable\",\n" +
+	"\fSignResponse\x12\x1c\n" +
+	"\tsignature\x18\x01 \x01(\fR\tsignature2u\n" +
+	"\rSignerService\x129\n" +
+	"\fGetPublicKey\x12\x14.pb.SignerKeyRequest\x1a\x13.pb.SignerPublicKey\x12)\n" +
+	"\x04Sign\x12\x0f.pb.SignRequest\x1a\x10.pb.SignResponseB\fZ\n" +
+	"pkg/p2p/pbb\x06proto3"
+
+var (
+	file_proto_signer_proto_rawDescOnce sync.Once
+	file_proto_signer_proto_rawDescData []byte
+)
+
+func file_proto_signer_proto_rawDescGZIP() []byte {
+	file_proto_signer_proto_rawDescOnce.Do(func() {
+		file_proto_signer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_signer_proto_rawDesc), len(file_proto_signer_proto_rawDesc)))
+	})
+	return file_proto_signer_proto_rawDescData
+}
+
+var file_proto_signer_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_signer_proto_goTypes = []any{
+	(*SignerKeyRequest)(nil), // 0: pb.SignerKeyRequest
+	(*SignerPublicKey)(nil),  // 1: pb.SignerPublicKey
+	(*SignRequest)(nil),      // 2: pb.SignRequest
+	(*SignResponse)(nil),     // 3: pb.SignResponse
+}
+var file_proto_signer_proto_depIdxs = []int32{
+	0, // 0: pb.SignerService.GetPublicKey:input_type -> pb.SignerKeyRequest
+	2, // 1: pb.SignerService.Sign:input_type -> pb.SignRequest
+	1, // 2: pb.SignerService.GetPublicKey:output_type -> pb.SignerPublicKey
+	3, // 3: pb.SignerService.Sign:output_type -> pb.SignResponse
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_signer_proto_init() }
+func file_proto_signer_proto_init() {
+	if File_proto_signer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_signer_proto_rawDesc), len(file_proto_signer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_signer_proto_goTypes,
+		DependencyIndexes: file_proto_signer_proto_depIdxs,
+		MessageInfos:      file_proto_signer_proto_msgTypes,
+	}.Build()
+	File_proto_signer_proto = out.File
+	file_proto_signer_proto_goTypes = nil
+	file_proto_signer_proto_depIdxs = nil
+}
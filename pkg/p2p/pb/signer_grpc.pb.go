@@ -0,0 +1,163 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.31.1
+// source: proto/signer.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SignerService_GetPublicKey_FullMethodName = "/pb.SignerService/GetPublicKey"
+	SignerService_Sign_FullMethodName         = "/pb.SignerService/Sign"
+)
+
+// SignerServiceClient is the client API for SignerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SignerService signs hashes with keys held by a separate signer process
+type SignerServiceClient interface {
+	GetPublicKey(ctx context.Context, in *SignerKeyRequest, opts ...grpc.CallOption) (*SignerPublicKey, error)
+	Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error)
+}
+
+type signerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignerServiceClient(cc grpc.ClientConnInterface) SignerServiceClient {
+	return &signerServiceClient{cc}
+}
+
+func (c *signerServiceClient) GetPublicKey(ctx context.Context, in *SignerKeyRequest, opts ...grpc.CallOption) (*SignerPublicKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignerPublicKey)
+	err := c.cc.Invoke(ctx, SignerService_GetPublicKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerServiceClient) Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignResponse)
+	err := c.cc.Invoke(ctx, SignerService_Sign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignerServiceServer is the server API for SignerService service.
+// All implementations must embed UnimplementedSignerServiceServer
+// for forward compatibility.
+//
+// SignerService signs hashes with keys held by a separate signer process
+type SignerServiceServer interface {
+	GetPublicKey(context.Context, *SignerKeyRequest) (*SignerPublicKey, error)
+	Sign(context.Context, *SignRequest) (*SignResponse, error)
+	mustEmbedUnimplementedSignerServiceServer()
+}
+
+// UnimplementedSignerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSignerServiceServer struct{}
+
+func (UnimplementedSignerServiceServer) GetPublicKey(context.Context, *SignerKeyRequest) (*SignerPublicKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicKey not implemented")
+}
+func (UnimplementedSignerServiceServer) Sign(context.Context, *SignRequest) (*SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sign not implemented")
+}
+func (UnimplementedSignerServiceServer) mustEmbedUnimplementedSignerServiceServer() {}
+func (UnimplementedSignerServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeSignerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignerServiceServer will
+// result in compilation errors.
+type UnsafeSignerServiceServer interface {
+	mustEmbedUnimplementedSignerServiceServer()
+}
+
+func RegisterSignerServiceServer(s grpc.ServiceRegistrar, srv SignerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSignerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SignerService_ServiceDesc, srv)
+}
+
+func _SignerService_GetPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignerKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServiceServer).GetPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignerService_GetPublicKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServiceServer).GetPublicKey(ctx, req.(*SignerKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SignerService_Sign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServiceServer).Sign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignerService_Sign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServiceServer).Sign(ctx, req.(*SignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SignerService_ServiceDesc is the grpc.ServiceDesc for SignerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SignerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.SignerService",
+	HandlerType: (*SignerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPublicKey",
+			Handler:    _SignerService_GetPublicKey_Handler,
+		},
+		{
+			MethodName: "Sign",
+			Handler:    _SignerService_Sign_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/signer.proto",
+}
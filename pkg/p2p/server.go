@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
@@ -11,7 +12,9 @@ import (
 	"time"
 
 	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/keys"
 	"golang-chain/pkg/p2p/pb"
+	"golang-chain/pkg/state"
 	"golang-chain/pkg/storage"
 	"golang-chain/pkg/wallet"
 
@@ -23,6 +26,9 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// MinTxFee is the lowest fee the leader accepts for version 3 transactions
+var MinTxFee float64
+
 type NodeServer struct {
 	pb.UnimplementedNodeServiceServer
 	DBPath     string
@@ -43,13 +49,66 @@ func (s *NodeServer) SendTransaction(ctx context.Context, tx *pb.Transaction) (*
 		}, nil
 	}
 
-	fromName := wallet.ResolveSenderName(tx.Sender)
-	toName := wallet.ResolveSenderName(tx.Receiver)
+	t := PbToTx(tx)
+
+	// For version 2 transactions this recovers the sender from the signature
+	if valid, err := t.Verify(); err != nil || !valid {
+		msg := "❌ Invalid transaction signature"
+		if err != nil {
+			msg = fmt.Sprintf("❌ Invalid transaction: %v", err)
+		}
+		return &pb.TxResponse{
+			Status:  "error",
+			Message: msg,
+		}, nil
+	}
+
+	from, to, err := state.TxAccounts(t)
+	if err != nil {
+		return &pb.TxResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("❌ %v", err),
+		}, nil
+	}
+
+	log.Printf("Received transaction from %s to %s (%.2f coins)", wallet.ResolveAddressName(from), wallet.ResolveAddressName(to), tx.Amount)
 
-	log.Printf("Received transaction from %s to %s (%.2f coins)", fromName, toName, tx.Amount)
+	if err := state.CheckAmount(t); err != nil {
+		return &pb.TxResponse{
+			Status:  "fail",
+			Message: fmt.Sprintf("❌ %v", err),
+		}, nil
+	}
+
+	// A signed transaction can't be submitted again once it's in a block
+	hash, err := t.Hash()
+	if err != nil {
+		return &pb.TxResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("❌ %v", err),
+		}, nil
+	}
+	if included, err := s.DB.HasTx(hash); err != nil {
+		return &pb.TxResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("❌ Failed to look up transaction: %v", err),
+		}, nil
+	} else if included {
+		return &pb.TxResponse{
+			Status:  "fail",
+			Message: fmt.Sprintf("❌ Transaction %x is already included", hash),
+		}, nil
+	}
+
+	if t.Version >= blockchain.TxVersion3 && t.Fee < MinTxFee {
+		return &pb.TxResponse{
+			Status:  "fail",
+			Message: fmt.Sprintf("❌ Fee %.8f is below the minimum fee %.8f", t.Fee, MinTxFee),
+		}, nil
+	}
 
 	// 🔍 Kiểm tra số dư trước
-	balance, err := s.DB.GetBalance(fromName)
+	balance, err := s.DB.GetBalance(from)
 	if err != nil {
 		return &pb.TxResponse{
 			Status:  "error",
@@ -57,23 +116,39 @@ func (s *NodeServer) SendTransaction(ctx context.Context, tx *pb.Transaction) (*
 		}, nil
 	}
 
-	amount := big.NewFloat(tx.Amount)
+	amount := big.NewFloat(tx.Amount + tx.Fee)
 	if balance.Cmp(amount) < 0 {
 		return &pb.TxResponse{
 			Status:  "fail",
-			Message: fmt.Sprintf("❌ Insufficient balance. You have %s, trying to send %.2f", balance.Text('f', 2), tx.Amount),
+			Message: fmt.Sprintf("❌ Insufficient balance. You have %s, trying to send %s", balance.Text('f', 2), amount.Text('f', 2)),
 		}, nil
 	}
 
-	t := &blockchain.Transaction{
-		Sender:    tx.Sender,
-		Receiver:  tx.Receiver,
-		Amount:    tx.Amount,
-		Timestamp: tx.Timestamp,
-		Signature: tx.Signature,
+	// Version 3 transactions must take the sender's next nonce. It's checked under the
+	// pool lock, so two transactions with the same nonce can't both get in.
+	err = blockchain.AddPendingTxIf(t, func(queued []*blockchain.Transaction) error {
+		if t.Version < blockchain.TxVersion3 {
+			return nil
+		}
+		next, err := s.nextNonce(from, queued)
+		if err != nil {
+			return fmt.Errorf("failed to get nonce: %w", err)
+		}
+		if t.Nonce != next {
+			return fmt.Errorf("%w: %d, the next nonce of the sender is %d", errBadNonce, t.Nonce, next)
+		}
+		return nil
+	})
+	if err != nil {
+		result := "error"
+		if errors.Is(err, blockchain.ErrTxPending) || errors.Is(err, errBadNonce) {
+			result = "fail"
+		}
+		return &pb.TxResponse{
+			Status:  result,
+			Message: fmt.Sprintf("❌ %v", err),
+		}, nil
 	}
-
-	blockchain.AddPendingTx(t)
 	log.Printf("📥 Transaction added to pending pool.")
 
 	return &pb.TxResponse{
@@ -82,6 +157,46 @@ func (s *NodeServer) SendTransaction(ctx context.Context, tx *pb.Transaction) (*
 	}, nil
 }
 
+// GetTxParams returns what a version 3 transaction from an address needs:
+// the sender's next nonce, counting its transactions waiting in the pool, and the minimum fee
+func (s *NodeServer) GetTxParams(ctx context.Context, req *pb.TxParamsRequest) (*pb.TxParams, error) {
+	var nonce uint64
+	err := blockchain.ViewPendingTxs(func(queued []*blockchain.Transaction) (err error) {
+		nonce, err = s.nextNonce(req.Address, queued)
+		return err
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get nonce: %v", err)
+	}
+	return &pb.TxParams{Nonce: nonce, MinFee: MinTxFee}, nil
+}
+
+// errBadNonce is returned for a version 3 transaction that doesn't carry the sender's next nonce
+var errBadNonce = errors.New("invalid nonce")
+
+// nextNonce returns the nonce the next version 3 transaction of address must have,
+// given the transactions queued in the pool (pending, or taken by the leader for a
+// block that isn't stored yet). Call it under the pool lock, so the stored nonce and
+// the queue are read consistently. Only the leader has queued transactions, other
+// nodes answer from the committed state.
+func (s *NodeServer) nextNonce(address string, queued []*blockchain.Transaction) (uint64, error) {
+	acc, err := s.DB.GetAccount(address)
+	if err != nil {
+		return 0, err
+	}
+	nonce := acc.Nonce
+	for _, tx := range queued {
+		if tx.Version < blockchain.TxVersion3 {
+			// Only version 3 transactions take a nonce
+			continue
+		}
+		if from, _, err := state.TxAccounts(tx); err == nil && from == address {
+			nonce++
+		}
+	}
+	return nonce, nil
+}
+
 func (s *NodeServer) Ping(ctx context.Context, e *pb.Empty) (*pb.TxResponse, error) {
 	return &pb.TxResponse{
 		Status:  "pong",
@@ -127,51 +242,57 @@ func StartGRPCServer(port, dbPath, nodeID string, db *storage.DB, state *NodeSta
 func (s *NodeServer) ProposeBlock(ctx context.Context, req *pb.VoteRequest) (*pb.VoteResponse, error) {
 	if *s.State != StateFollower {
 		log.Println("⚠️ Vote rejected: I am not a follower.")
-		return &pb.VoteResponse{
-			NodeId:   s.NodeID,
-			Approved: false,
-		}, nil
+		return s.vote(req, false)
 	}
 
 	block := req.Block
 	log.Printf("[Follower] Received proposed block: %s", block.CurrentBlockHash)
 
+	if err := checkProposal(req); err != nil {
+		log.Println("❌ Rejected proposal:", err)
+		return s.vote(req, false)
+	}
+
 	latestBlock, err := s.DB.GetLatestBlock()
 	if err != nil {
 		log.Println("⚠️ No latest block found, assuming fresh node")
 		if block.Height == 0 {
 			log.Println("✅ Accepting genesis block proposal.")
-			return &pb.VoteResponse{
-				NodeId:   s.NodeID,
-				Approved: true,
-			}, nil
+			return s.vote(req, true)
 		}
 		log.Println("❌ Rejected: Genesis block must have height 0")
-		return &pb.VoteResponse{
-			NodeId:   s.NodeID,
-			Approved: false,
-		}, nil
+		return s.vote(req, false)
+	}
+
+	preState, err := s.DB.LoadState()
+	if err != nil {
+		log.Println("❌ Failed to load state:", err)
+		return s.vote(req, false)
 	}
 
 	newBlock := convertPbBlock(block)
-	isValid := consensus.VerifyBlock(newBlock, latestBlock)
+	isValid := consensus.VerifyBlock(newBlock, latestBlock, preState)
+
+	return s.vote(req, isValid)
+}
 
-	return &pb.VoteResponse{
+// vote answers a proposal, signing the vote with the validator key if the node has one
+func (s *NodeServer) vote(req *pb.VoteRequest, approved bool) (*pb.VoteResponse, error) {
+	vote := &pb.VoteResponse{
 		NodeId:   s.NodeID,
-		Approved: isValid,
-	}, nil
+		Approved: approved,
+	}
+	if err := signVote(vote, req.GetBlock().GetCurrentBlockHash()); err != nil {
+		log.Println("❌ Failed to sign vote:", err)
+		return nil, status.Error(codes.Unavailable, "failed to sign vote")
+	}
+	return vote, nil
 }
 
 func convertPbBlock(pbBlock *pb.Block) *blockchain.Block {
 	var txs []*blockchain.Transaction
 	for _, tx := range pbBlock.Transactions {
-		txs = append(txs, &blockchain.Transaction{
-			Sender:    append([]byte(nil), tx.Sender...),
-			Receiver:  append([]byte(nil), tx.Receiver...),
-			Amount:    tx.Amount,
-			Timestamp: tx.Timestamp,
-			Signature: append([]byte(nil), tx.Signature...),
-		})
+		txs = append(txs, PbToTx(tx))
 	}
 
 	block := &blockchain.Block{
@@ -179,19 +300,77 @@ func convertPbBlock(pbBlock *pb.Block) *blockchain.Block {
 		PrevBlockHash:    pbBlock.PrevBlockHash,
 		CurrentBlockHash: pbBlock.CurrentBlockHash,
 		Height:           pbBlock.Height,
+		StateRoot:        pbBlock.StateRoot,
+		Version:          pbBlock.Version,
 	}
 
-	block.MerkleRoot = blockchain.CalculateMerkleRoot(txs)
+	block.MerkleRoot = blockchain.CalculateMerkleRoot(txs, block.Version)
 
 	return block
 }
 
-func (s *NodeServer) CommitBlock(ctx context.Context, pbBlock *pb.Block) (*pb.TxResponse, error) {
-	block := convertPbBlock(pbBlock)
+// PbToHeader returns the block header sent on the wire as header
+func PbToHeader(header *pb.BlockHeader) *blockchain.BlockHeader {
+	return &blockchain.BlockHeader{
+		MerkleRoot:       header.MerkleRoot,
+		PrevBlockHash:    header.PrevBlockHash,
+		CurrentBlockHash: header.CurrentBlockHash,
+		Height:           header.Height,
+		StateRoot:        header.StateRoot,
+		Version:          header.Version,
+	}
+}
+
+func convertHeader(header *blockchain.BlockHeader) *pb.BlockHeader {
+	return &pb.BlockHeader{
+		MerkleRoot:       header.MerkleRoot,
+		PrevBlockHash:    header.PrevBlockHash,
+		CurrentBlockHash: header.CurrentBlockHash,
+		Height:           header.Height,
+		StateRoot:        header.StateRoot,
+		Version:          header.Version,
+	}
+}
 
-	err := s.DB.SaveBlock(block)
+// CommitBlock saves a block the leader collected enough votes for. Unless AllowUnsigned
+// is set the commit must carry the proposer's signed proposal and enough signed
+// approvals. Either way the block must follow the local tip and match the state root.
+func (s *NodeServer) CommitBlock(ctx context.Context, req *pb.CommitRequest) (*pb.TxResponse, error) {
+	proposal := req.GetProposal()
+	if proposal.GetBlock() == nil {
+		return nil, status.Error(codes.InvalidArgument, "commit without a block")
+	}
+	block := convertPbBlock(proposal.Block)
+
+	if !AllowUnsigned {
+		if err := checkProposal(proposal); err != nil {
+			log.Println("❌ Rejected commit:", err)
+			return nil, status.Errorf(codes.PermissionDenied, "invalid proposal: %v", err)
+		}
+		if n := countApprovals(req.Votes, proposal.Block.CurrentBlockHash); n < CommitQuorum {
+			log.Printf("❌ Rejected commit: %d approvals, %d needed", n, CommitQuorum)
+			return nil, status.Errorf(codes.PermissionDenied, "%d signed approvals, %d needed", n, CommitQuorum)
+		}
+	}
+
+	latestBlock, err := s.DB.GetLatestBlock()
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.Internal, "Failed to get latest block: %v", err)
+	}
+	if latestBlock.CurrentBlockHash == block.CurrentBlockHash {
+		return &pb.TxResponse{Status: "success", Message: "block already saved"}, nil
+	}
+	preState, err := s.DB.LoadState()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to load state: %v", err)
+	}
+	if !consensus.VerifyBlock(block, latestBlock, preState) {
+		log.Println("❌ Rejected commit of invalid block", block.CurrentBlockHash)
+		return nil, status.Error(codes.InvalidArgument, "invalid block")
+	}
+
+	if err := s.DB.SaveBlock(block); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save block: %v", err)
 	}
 
 	log.Printf("[Follower] Block committed: %s", block.CurrentBlockHash)
@@ -212,6 +391,9 @@ func (s *NodeServer) GetLatestBlock(ctx context.Context, _ *pb.Empty) (*pb.Block
 
 func (s *NodeServer) GetBlock(ctx context.Context, req *pb.BlockRequest) (*pb.BlockResponse, error) {
 	blk, err := s.DB.GetBlock([]byte(req.Hash))
+	if err == storage.ErrPruned {
+		return nil, status.Errorf(codes.NotFound, "Block %s has been pruned", req.Hash)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -222,13 +404,7 @@ func (s *NodeServer) GetBlock(ctx context.Context, req *pb.BlockRequest) (*pb.Bl
 func ConvertBlockToPb(block *blockchain.Block) *pb.Block {
 	var txs []*pb.Transaction
 	for _, tx := range block.Transactions {
-		txs = append(txs, &pb.Transaction{
-			Sender:    tx.Sender,
-			Receiver:  tx.Receiver,
-			Amount:    tx.Amount,
-			Timestamp: tx.Timestamp,
-			Signature: tx.Signature,
-		})
+		txs = append(txs, TxToPb(tx))
 	}
 
 	return &pb.Block{
@@ -237,17 +413,78 @@ func ConvertBlockToPb(block *blockchain.Block) *pb.Block {
 		PrevBlockHash:    block.PrevBlockHash,
 		CurrentBlockHash: block.CurrentBlockHash,
 		Height:           block.Height,
+		StateRoot:        block.StateRoot,
+		Version:          block.Version,
+	}
+}
+
+// TxToPb returns the wire form of a transaction
+func TxToPb(tx *blockchain.Transaction) *pb.Transaction {
+	return &pb.Transaction{
+		Sender:    tx.Sender,
+		Receiver:  tx.Receiver,
+		Amount:    tx.Amount,
+		Timestamp: tx.Timestamp,
+		Signature: tx.Signature,
+		KeyType:   uint32(tx.KeyType),
+		Version:   tx.Version,
+		Nonce:     tx.Nonce,
+		Fee:       tx.Fee,
+	}
+}
+
+// PbToTx returns the transaction sent on the wire as tx. It copies the byte
+// fields, so the transaction doesn't share memory with the message.
+func PbToTx(tx *pb.Transaction) *blockchain.Transaction {
+	return &blockchain.Transaction{
+		Sender:    append([]byte(nil), tx.Sender...),
+		Receiver:  append([]byte(nil), tx.Receiver...),
+		Amount:    tx.Amount,
+		Timestamp: tx.Timestamp,
+		Signature: append([]byte(nil), tx.Signature...),
+		KeyType:   keys.Scheme(tx.KeyType),
+		Version:   tx.Version,
+		Nonce:     tx.Nonce,
+		Fee:       tx.Fee,
 	}
 }
 
 func (s *NodeServer) GetBlockByHeight(ctx context.Context, req *pb.HeightRequest) (*pb.BlockResponse, error) {
 	block, err := s.DB.GetBlockByHeight(req.Height)
+	if err == storage.ErrPruned {
+		return nil, status.Errorf(codes.NotFound, "Block at height %d has been pruned", req.Height)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &pb.BlockResponse{Block: ConvertBlockToPb(block)}, nil
 }
 
+// GetHeaderByHeight returns only the header of a block, which stays available after pruning
+func (s *NodeServer) GetHeaderByHeight(ctx context.Context, req *pb.HeightRequest) (*pb.BlockHeader, error) {
+	header, err := s.DB.GetHeaderByHeight(req.Height)
+	if err == storage.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "No header at height %d", req.Height)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get header: %v", err)
+	}
+	return convertHeader(header), nil
+}
+
+// GetRetainedRange tells peers which heights this node can serve headers and full blocks for
+func (s *NodeServer) GetRetainedRange(ctx context.Context, _ *pb.Empty) (*pb.RetainedRange, error) {
+	rng, err := s.DB.GetRetainedRange()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get retained range: %v", err)
+	}
+	return &pb.RetainedRange{
+		HeadersFrom: rng.HeadersFrom,
+		BodiesFrom:  rng.BodiesFrom,
+		Latest:      rng.Latest,
+	}, nil
+}
+
 func DetectLeader(peers []string) string {
 	for _, peer := range peers {
 		conn, err := grpc.Dial(peer, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock(), grpc.WithTimeout(2*time.Second))
@@ -272,16 +509,178 @@ func DetectLeader(peers []string) string {
 	return ""
 }
 
+// GetBalance returns the account at the requested address. With Prove set it also
+// returns a proof of the account against the state root of the latest block header.
 func (s *NodeServer) GetBalance(ctx context.Context, req *pb.BalanceRequest) (*pb.BalanceResponse, error) {
-	bal, err := s.DB.GetBalance(req.Name)
+	if req.Prove {
+		return s.getProvenBalance(req.Address)
+	}
+
+	acc, err := s.DB.GetAccount(req.Address)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to get balance: %v", err)
 	}
 	return &pb.BalanceResponse{
-		Balance: bal.Text('f', 2),
+		Balance: acc.Balance.Text('f', 2),
+		Nonce:   acc.Nonce,
 	}, nil
 }
 
+func (s *NodeServer) getProvenBalance(address string) (*pb.BalanceResponse, error) {
+	latest, err := s.DB.GetLatestBlock()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get latest block: %v", err)
+	}
+	if latest.StateRoot == "" {
+		return nil, status.Error(codes.FailedPrecondition, "Latest block has no state root")
+	}
+
+	st, err := s.DB.LoadState()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to load state: %v", err)
+	}
+	// A block may have been committed between the two reads
+	if root, err := st.Root(); err != nil || root != latest.StateRoot {
+		return nil, status.Error(codes.Unavailable, "State changed while building the proof, try again")
+	}
+
+	acc, err := st.Get(address)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get balance: %v", err)
+	}
+	proof, err := st.Prove(address)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to build proof: %v", err)
+	}
+	encoded, err := acc.Encode()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to encode account: %v", err)
+	}
+
+	return &pb.BalanceResponse{
+		Balance: acc.Balance.Text('f', 2),
+		Nonce:   acc.Nonce,
+		Header:  convertHeader(latest.Header()),
+		Proof: &pb.StateProof{
+			Account:  encoded,
+			Bitmap:   proof.Bitmap,
+			Siblings: proof.Siblings,
+		},
+	}, nil
+}
+
+// GetAccountHistory returns one page of an account's transactions, newest first.
+// Limit defaults to 10 and is capped at 100 entries per page.
+func (s *NodeServer) GetAccountHistory(ctx context.Context, req *pb.AccountHistoryRequest) (*pb.AccountHistoryResponse, error) {
+	if req.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	locs, total, err := s.DB.GetAccountHistory(req.Address, int(req.Offset), limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get history: %v", err)
+	}
+
+	var entries []*pb.HistoryEntry
+	for _, loc := range locs {
+		blk, err := s.DB.GetBlockByHeight(loc.Height)
+		if err == storage.ErrPruned {
+			// Pruning ran since the index was read
+			return nil, status.Errorf(codes.NotFound, "Block at height %d has been pruned", loc.Height)
+		}
+		if err != nil || loc.Index >= len(blk.Transactions) {
+			return nil, status.Errorf(codes.Internal, "Indexed tx %d at height %d not found", loc.Index, loc.Height)
+		}
+		tx := blk.Transactions[loc.Index]
+		from, to, err := state.TxAccounts(tx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Indexed tx %d at height %d: %v", loc.Index, loc.Height, err)
+		}
+		entries = append(entries, &pb.HistoryEntry{
+			Height:      loc.Height,
+			Index:       int32(loc.Index),
+			From:        from,
+			To:          to,
+			Transaction: TxToPb(tx),
+		})
+	}
+
+	return &pb.AccountHistoryResponse{
+		Entries: entries,
+		Total:   int32(total),
+	}, nil
+}
+
+// GetTxProof returns the Merkle inclusion proof of a transaction together with
+// the header of the block that contains it, so the client can check it offline.
+func (s *NodeServer) GetTxProof(ctx context.Context, req *pb.TxProofRequest) (*pb.TxProofResponse, error) {
+	loc, err := s.DB.GetTxLocation(req.TxHash)
+	if err == storage.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "Transaction %s not found", req.TxHash)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to look up transaction: %v", err)
+	}
+
+	blk, err := s.DB.GetBlockByHeight(loc.Height)
+	if err == storage.ErrPruned {
+		return nil, status.Errorf(codes.NotFound, "Block at height %d has been pruned", loc.Height)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get block at height %d: %v", loc.Height, err)
+	}
+	proof, err := blockchain.BuildMerkleProof(blk.Transactions, loc.Index, blk.Version)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to build proof: %v", err)
+	}
+
+	return &pb.TxProofResponse{
+		Header:      convertHeader(blk.Header()),
+		Index:       int32(proof.Index),
+		TreeSize:    int32(proof.TreeSize),
+		Siblings:    proof.Siblings,
+		Transaction: TxToPb(blk.Transactions[loc.Index]),
+	}, nil
+}
+
+// ListSnapshots returns the manifests of the state snapshots this node can serve, newest first
+func (s *NodeServer) ListSnapshots(ctx context.Context, _ *pb.Empty) (*pb.SnapshotList, error) {
+	manifests, err := s.DB.ListSnapshots()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to list snapshots: %v", err)
+	}
+
+	list := &pb.SnapshotList{}
+	for _, m := range manifests {
+		list.Snapshots = append(list.Snapshots, &pb.SnapshotManifest{
+			Height:    m.Height,
+			BlockHash: m.BlockHash,
+			StateRoot: m.StateRoot,
+			Chunks:    m.Chunks,
+		})
+	}
+	return list, nil
+}
+
+// GetSnapshotChunk returns one chunk of a state snapshot
+func (s *NodeServer) GetSnapshotChunk(ctx context.Context, req *pb.SnapshotChunkRequest) (*pb.SnapshotChunk, error) {
+	data, err := s.DB.GetSnapshotChunk(req.Height, int(req.Index))
+	if err == storage.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "Chunk %d of snapshot %d not found", req.Index, req.Height)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get snapshot chunk: %v", err)
+	}
+	return &pb.SnapshotChunk{Data: data}, nil
+}
+
 var priorityMap = make(map[string]int)
 
 // ExchangePriority: dùng mutex và log kỹ càng
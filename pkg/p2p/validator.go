@@ -0,0 +1,102 @@
+package p2p
+
+import (
+	"errors"
+	"log"
+
+	"golang-chain/pkg/consensus"
+	"golang-chain/pkg/keys"
+	"golang-chain/pkg/p2p/pb"
+	"golang-chain/pkg/wallet"
+)
+
+// ValidatorSigner signs the blocks this node proposes and its votes. Without one,
+// proposals and votes are sent unsigned.
+var ValidatorSigner wallet.Signer
+
+// Validators holds the addresses of the validator keys whose proposals and votes
+// are accepted
+var Validators map[string]bool
+
+// AllowUnsigned accepts unsigned proposals and votes and commits without a quorum of
+// signed approvals. It's only meant for networks without Validators.
+var AllowUnsigned bool
+
+// CommitQuorum is the number of approving votes a block needs to be committed
+const CommitQuorum = 2
+
+// signProposal signs a proposal of its block with ValidatorSigner, if there's one
+func signProposal(req *pb.VoteRequest) error {
+	if ValidatorSigner == nil {
+		return nil
+	}
+	sig, err := ValidatorSigner.Sign(consensus.ProposalHash(req.Block.CurrentBlockHash))
+	if err != nil {
+		return err
+	}
+	pub := ValidatorSigner.Public()
+	req.ProposerKey = pub.Bytes()
+	req.ProposerKeyType = uint32(pub.Scheme())
+	req.Signature = sig
+	return nil
+}
+
+// signVote signs a vote on the block with hash blockHash with ValidatorSigner, if there's one
+func signVote(vote *pb.VoteResponse, blockHash string) error {
+	if ValidatorSigner == nil {
+		return nil
+	}
+	sig, err := ValidatorSigner.Sign(consensus.VoteHash(blockHash, vote.NodeId, vote.Approved))
+	if err != nil {
+		return err
+	}
+	pub := ValidatorSigner.Public()
+	vote.VoterKey = pub.Bytes()
+	vote.VoterKeyType = uint32(pub.Scheme())
+	vote.Signature = sig
+	return nil
+}
+
+// checkValidator verifies the signature of a proposal or vote over hash and returns
+// the address of the validator that made it, or "" for an accepted unsigned one
+func checkValidator(keyType uint32, key, sig, hash []byte) (string, error) {
+	if len(sig) == 0 {
+		if !AllowUnsigned {
+			return "", errors.New("not signed by a validator")
+		}
+		return "", nil
+	}
+	return consensus.VerifyValidator(Validators, keys.Scheme(keyType), key, sig, hash)
+}
+
+// checkProposal verifies the proposer's signature of a proposal
+func checkProposal(req *pb.VoteRequest) error {
+	_, err := checkValidator(req.ProposerKeyType, req.ProposerKey, req.Signature, consensus.ProposalHash(req.Block.CurrentBlockHash))
+	return err
+}
+
+// countApprovals counts the approving votes on the block with hash blockHash that
+// carry a valid signature, once per validator key
+func countApprovals(votes []*pb.VoteResponse, blockHash string) int {
+	count := 0
+	seen := make(map[string]bool)
+	for _, v := range votes {
+		if !v.Approved {
+			continue
+		}
+		address, err := checkValidator(v.VoterKeyType, v.VoterKey, v.Signature, consensus.VoteHash(blockHash, v.NodeId, v.Approved))
+		if err != nil {
+			log.Printf("⚠️ Ignoring vote of %s: %v", v.NodeId, err)
+			continue
+		}
+		if address != "" {
+			if seen[address] {
+				log.Printf("⚠️ Ignoring second vote of validator %s", address)
+				continue
+			}
+			seen[address] = true
+		}
+		count++
+	}
+	return count
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"encoding/json"
+	"math/big"
+)
+
+// Account is the state kept for every address: its balance and
+// the number of version 3 transactions it has sent so far (its nonce)
+type Account struct {
+	Balance *big.Float
+	Nonce   uint64
+}
+
+// balancePrec is the precision of all balance arithmetic.
+// It must be the same on every node, otherwise state roots would diverge.
+const balancePrec = 256
+
+// NewAccount returns an empty account with a zero balance
+func NewAccount() *Account {
+	return &Account{Balance: new(big.Float).SetPrec(balancePrec)}
+}
+
+// Copy returns a deep copy of the account
+func (a *Account) Copy() *Account {
+	return &Account{
+		Balance: new(big.Float).Copy(a.Balance),
+		Nonce:   a.Nonce,
+	}
+}
+
+// IsEmpty reports whether the account holds nothing worth storing
+func (a *Account) IsEmpty() bool {
+	return a.Balance.Sign() == 0 && a.Nonce == 0
+}
+
+// accountJSON is the stored form of an account.
+// The balance is kept as a string to avoid losing precision when marshalling floats.
+type accountJSON struct {
+	Balance string `json:"balance"`
+	Nonce   uint64 `json:"nonce"`
+}
+
+// Encode serializes the account. The encoding is deterministic,
+// it's what the state tree commits to.
+func (a *Account) Encode() ([]byte, error) {
+	return json.Marshal(accountJSON{
+		Balance: a.Balance.Text('f', 8),
+		Nonce:   a.Nonce,
+	})
+}
+
+// DecodeAccount parses an account produced by Encode
+func DecodeAccount(data []byte) (*Account, error) {
+	var raw accountJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	bal, _, err := big.ParseFloat(raw.Balance, 10, balancePrec, big.ToNearestEven)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{Balance: bal, Nonce: raw.Nonce}, nil
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Proof shows what account is stored at an address under a given state root.
+// It proves absent accounts too: they sit in the tree as empty leaves.
+//
+// Most siblings on the path of a sparse tree are empty, so only the non-empty
+// ones are listed, from the root downwards. Bit i of Bitmap (most significant
+// bit first, like the key bits) is set when the sibling at depth i is listed.
+type Proof struct {
+	Bitmap   string
+	Siblings []string
+}
+
+// Prove creates the proof for the account at address.
+// Like Root it needs a State that holds the complete account set (no Source).
+func (s *State) Prove(address string) (*Proof, error) {
+	leaves, err := s.leaves()
+	if err != nil {
+		return nil, err
+	}
+
+	key := treeKey(address)
+	var bitmap [32]byte
+	proof := &Proof{}
+	for depth := 0; depth < treeDepth; depth++ {
+		split := sort.Search(len(leaves), func(i int) bool {
+			return bitAt(leaves[i].key, depth) == 1
+		})
+		var sibling [32]byte
+		if bitAt(key, depth) == 0 {
+			sibling = subtreeRoot(leaves[split:], depth+1)
+			leaves = leaves[:split]
+		} else {
+			sibling = subtreeRoot(leaves[:split], depth+1)
+			leaves = leaves[split:]
+		}
+		if sibling != emptyHash {
+			bitmap[depth/8] |= 1 << (7 - uint(depth%8))
+			proof.Siblings = append(proof.Siblings, hex.EncodeToString(sibling[:]))
+		}
+	}
+	proof.Bitmap = hex.EncodeToString(bitmap[:])
+	return proof, nil
+}
+
+// VerifyProof checks that acc is the account stored at address under the hex-encoded stateRoot.
+// Pass an empty account to check that the address has no account.
+func VerifyProof(stateRoot, address string, acc *Account, proof *Proof) (bool, error) {
+	raw, err := hex.DecodeString(proof.Bitmap)
+	if err != nil || len(raw) != treeDepth/8 {
+		return false, errors.New("invalid proof bitmap")
+	}
+	var bitmap [32]byte
+	copy(bitmap[:], raw)
+	siblings := make([][32]byte, len(proof.Siblings))
+	for i, s := range proof.Siblings {
+		b, err := hex.DecodeString(s)
+		if err != nil || len(b) != 32 {
+			return false, fmt.Errorf("invalid sibling hash %q", s)
+		}
+		copy(siblings[i][:], b)
+	}
+
+	key := treeKey(address)
+	node := emptyHash
+	if !acc.IsEmpty() {
+		value, err := acc.Encode()
+		if err != nil {
+			return false, err
+		}
+		node = leafHash(key, value)
+	}
+
+	// Walk up from the leaf, taking listed siblings from the end
+	next := len(siblings)
+	for depth := treeDepth - 1; depth >= 0; depth-- {
+		sibling := emptyHash
+		if bitAt(bitmap, depth) == 1 {
+			if next == 0 {
+				return false, nil
+			}
+			next--
+			sibling = siblings[next]
+		}
+		if bitAt(key, depth) == 0 {
+			node = innerHash(node, sibling)
+		} else {
+			node = innerHash(sibling, node)
+		}
+	}
+	if next != 0 {
+		return false, nil
+	}
+	return hex.EncodeToString(node[:]) == stateRoot, nil
+}
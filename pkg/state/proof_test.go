@@ -0,0 +1,104 @@
+package state
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// proofState returns a state holding a few accounts, and its root
+func proofState(t *testing.T) (*State, string) {
+	t.Helper()
+	st := New(nil)
+	for i, addr := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		st.Set(addr, funded(float64(i+1)))
+	}
+	return st, root(t, st)
+}
+
+func prove(t *testing.T, st *State, address string) *Proof {
+	t.Helper()
+	proof, err := st.Prove(address)
+	if err != nil {
+		t.Fatalf("Prove(%s): %v", address, err)
+	}
+	return proof
+}
+
+// flipHex flips the lowest bit of the last byte of a hex string
+func flipHex(s string) string {
+	b, _ := hex.DecodeString(s)
+	b[len(b)-1] ^= 1
+	return hex.EncodeToString(b)
+}
+
+func TestVerifyProof(t *testing.T) {
+	st, stateRoot := proofState(t)
+	bob, _ := st.Get("bob")
+	alice := prove(t, st, "alice")
+	bobProof := prove(t, st, "bob")
+	absent := prove(t, st, "mallory")
+
+	// The proof of bob with one of its listed siblings changed
+	tamperedSibling := *bobProof
+	tamperedSibling.Siblings = append([]string(nil), bobProof.Siblings...)
+	tamperedSibling.Siblings[0] = flipHex(tamperedSibling.Siblings[0])
+
+	// The deepest listed sibling claimed to be one level lower
+	movedSibling := *bobProof
+	movedSibling.Bitmap = flipHex(bobProof.Bitmap)
+
+	truncated := *bobProof
+	truncated.Siblings = bobProof.Siblings[:len(bobProof.Siblings)-1]
+
+	extra := *bobProof
+	extra.Siblings = append(append([]string(nil), bobProof.Siblings...), bobProof.Siblings[0])
+
+	tests := []struct {
+		name    string
+		root    string
+		address string
+		acc     *Account
+		proof   *Proof
+		want    bool
+	}{
+		{"inclusion", stateRoot, "bob", bob, bobProof, true},
+		{"non-inclusion of an absent account", stateRoot, "mallory", NewAccount(), absent, true},
+		{"absent account claimed to hold coins", stateRoot, "mallory", funded(1), absent, false},
+		{"present account claimed to be absent", stateRoot, "bob", NewAccount(), bobProof, false},
+		{"wrong balance", stateRoot, "bob", funded(100), bobProof, false},
+		{"proof of another account", stateRoot, "bob", bob, alice, false},
+		{"tampered sibling", stateRoot, "bob", bob, &tamperedSibling, false},
+		{"tampered bitmap", stateRoot, "bob", bob, &movedSibling, false},
+		{"missing sibling", stateRoot, "bob", bob, &truncated, false},
+		{"extra sibling", stateRoot, "bob", bob, &extra, false},
+		{"wrong root", flipHex(stateRoot), "bob", bob, bobProof, false},
+		{"empty tree", strings.Repeat("0", 64), "bob", NewAccount(), prove(t, New(nil), "bob"), true},
+	}
+	for _, tc := range tests {
+		ok, err := VerifyProof(tc.root, tc.address, tc.acc, tc.proof)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		} else if ok != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, ok, tc.want)
+		}
+	}
+}
+
+func TestVerifyProofMalformed(t *testing.T) {
+	st, stateRoot := proofState(t)
+	bob, _ := st.Get("bob")
+	valid := prove(t, st, "bob")
+
+	tests := map[string]*Proof{
+		"bitmap isn't hex":  {Bitmap: "zz", Siblings: valid.Siblings},
+		"short bitmap":      {Bitmap: valid.Bitmap[:62], Siblings: valid.Siblings},
+		"sibling isn't hex": {Bitmap: valid.Bitmap, Siblings: append([]string{"zz"}, valid.Siblings[1:]...)},
+		"short sibling":     {Bitmap: valid.Bitmap, Siblings: append([]string{valid.Siblings[0][:62]}, valid.Siblings[1:]...)},
+	}
+	for name, proof := range tests {
+		if ok, err := VerifyProof(stateRoot, "bob", bob, proof); ok || err == nil {
+			t.Errorf("%s: got %v, %v, want an error", name, ok, err)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package state
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"golang-chain/pkg/blockchain"
+)
+
+// ErrNonce is returned when a transaction's nonce isn't the sender's next one
+var ErrNonce = errors.New("wrong nonce")
+
+// ErrInsufficientBalance is returned when the sender can't pay a transaction's amount and fee
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// ErrInvalidAmount is returned for a non-positive amount, a negative fee, or a NaN or infinite value
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ErrDuplicateTx is returned for a transaction that was already applied
+var ErrDuplicateTx = errors.New("transaction already included")
+
+// Source provides accounts that aren't loaded into a State yet.
+// GetAccount must return an empty account for unknown addresses.
+type Source interface {
+	GetAccount(address string) (*Account, error)
+}
+
+// TxIndex tells whether a transaction hash is included in the chain the state was built from
+type TxIndex interface {
+	HasTx(hash []byte) (bool, error)
+}
+
+// State is an in-memory view of the account state that transactions are applied to.
+// Accounts missing from memory are fetched from the Source on first access;
+// a State without a Source must hold every account, which Root relies on.
+// Transactions are applied at most once: the State remembers the hashes it applied
+// and, with a TxIndex, refuses the ones already included before it.
+type State struct {
+	source   Source
+	txIndex  TxIndex
+	accounts map[string]*Account
+	changed  map[string]bool
+	applied  map[string]bool
+}
+
+// New creates a state that loads accounts from src, which may be nil
+func New(src Source) *State {
+	return &State{
+		source:   src,
+		accounts: make(map[string]*Account),
+		changed:  make(map[string]bool),
+		applied:  make(map[string]bool),
+	}
+}
+
+// UseTxIndex makes ApplyTx refuse transactions that idx holds
+func (s *State) UseTxIndex(idx TxIndex) {
+	s.txIndex = idx
+}
+
+// Get returns the account at address. The returned account must not be modified.
+func (s *State) Get(address string) (*Account, error) {
+	if acc, ok := s.accounts[address]; ok {
+		return acc, nil
+	}
+	if s.source == nil {
+		return NewAccount(), nil
+	}
+	acc, err := s.source.GetAccount(address)
+	if err != nil {
+		return nil, err
+	}
+	s.accounts[address] = acc
+	return acc, nil
+}
+
+// Set stores the account at address
+func (s *State) Set(address string, acc *Account) {
+	s.accounts[address] = acc
+	s.changed[address] = true
+}
+
+// Changed returns the accounts modified since the state was created
+func (s *State) Changed() map[string]*Account {
+	out := make(map[string]*Account, len(s.changed))
+	for addr := range s.changed {
+		out[addr] = s.accounts[addr]
+	}
+	return out
+}
+
+// Accounts returns every account held in memory.
+// The returned accounts must not be modified.
+func (s *State) Accounts() map[string]*Account {
+	out := make(map[string]*Account, len(s.accounts))
+	for addr, acc := range s.accounts {
+		out[addr] = acc
+	}
+	return out
+}
+
+// Copy returns an independent copy of the state sharing the same source
+func (s *State) Copy() *State {
+	cp := New(s.source)
+	cp.txIndex = s.txIndex
+	for addr, acc := range s.accounts {
+		cp.accounts[addr] = acc.Copy()
+	}
+	for addr := range s.changed {
+		cp.changed[addr] = true
+	}
+	for hash := range s.applied {
+		cp.applied[hash] = true
+	}
+	return cp
+}
+
+// TxAccounts returns the sender and receiver accounts of a transaction.
+// The sender is the address of the public key embedded in the transaction,
+// the receiver is the address stored in the transaction as-is.
+func TxAccounts(tx *blockchain.Transaction) (from, to string, err error) {
+	pub, err := tx.SenderKey()
+	if err != nil {
+		return "", "", fmt.Errorf("invalid sender key: %w", err)
+	}
+	return pub.Address(), string(tx.Receiver), nil
+}
+
+// CheckAmount fails unless the transaction moves a positive, finite amount and pays
+// a non-negative, finite fee
+func CheckAmount(tx *blockchain.Transaction) error {
+	if math.IsNaN(tx.Amount) || math.IsInf(tx.Amount, 0) || tx.Amount <= 0 {
+		return fmt.Errorf("%w: amount %v must be positive", ErrInvalidAmount, tx.Amount)
+	}
+	if math.IsNaN(tx.Fee) || math.IsInf(tx.Fee, 0) || tx.Fee < 0 {
+		return fmt.Errorf("%w: fee %v must not be negative", ErrInvalidAmount, tx.Fee)
+	}
+	return nil
+}
+
+// ApplyTx moves the amount from sender to receiver. A version 3 transaction must
+// carry the sender's current nonce, which it bumps, and its fee is taken from the
+// sender and burned. The sender must hold the amount plus the fee,
+// and the transaction must not have been applied before.
+// The state is left untouched if the transaction can't be applied.
+func (s *State) ApplyTx(tx *blockchain.Transaction) error {
+	if err := CheckAmount(tx); err != nil {
+		return err
+	}
+	from, to, err := TxAccounts(tx)
+	if err != nil {
+		return err
+	}
+	hash, err := tx.Hash()
+	if err != nil {
+		return err
+	}
+	if err := s.checkNotApplied(hash); err != nil {
+		return err
+	}
+
+	// Load both accounts before changing anything
+	sender, err := s.Get(from)
+	if err != nil {
+		return err
+	}
+	if _, err := s.Get(to); err != nil {
+		return err
+	}
+	if tx.Version >= blockchain.TxVersion3 && tx.Nonce != sender.Nonce {
+		return fmt.Errorf("%w: transaction has nonce %d, account is at %d", ErrNonce, tx.Nonce, sender.Nonce)
+	}
+	cost := new(big.Float).SetPrec(balancePrec).Add(big.NewFloat(tx.Amount), big.NewFloat(tx.Fee))
+	if sender.Balance.Cmp(cost) < 0 {
+		return fmt.Errorf("%w: account has %s, transaction needs %s", ErrInsufficientBalance, sender.Balance.Text('f', 8), cost.Text('f', 8))
+	}
+
+	sender = sender.Copy()
+	sender.Balance.Sub(sender.Balance, cost)
+	if tx.Version >= blockchain.TxVersion3 {
+		sender.Nonce++
+	}
+	s.Set(from, sender)
+
+	// Read the receiver after the sender is updated, they may be the same account
+	receiver, _ := s.Get(to)
+	receiver = receiver.Copy()
+	receiver.Balance.Add(receiver.Balance, big.NewFloat(tx.Amount))
+	s.Set(to, receiver)
+
+	s.applied[string(hash)] = true
+	return nil
+}
+
+// checkNotApplied fails if the transaction hash was applied to this state or is in its TxIndex
+func (s *State) checkNotApplied(hash []byte) error {
+	if s.applied[string(hash)] {
+		return fmt.Errorf("%w: %x", ErrDuplicateTx, hash)
+	}
+	if s.txIndex == nil {
+		return nil
+	}
+	included, err := s.txIndex.HasTx(hash)
+	if err != nil {
+		return err
+	}
+	if included {
+		return fmt.Errorf("%w: %x", ErrDuplicateTx, hash)
+	}
+	return nil
+}
+
+// ApplyBlock applies every transaction of the block in order
+func (s *State) ApplyBlock(block *blockchain.Block) error {
+	for i, tx := range block.Transactions {
+		if err := s.ApplyTx(tx); err != nil {
+			return fmt.Errorf("tx %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Root computes the hex-encoded root of the state tree over all accounts.
+// Only call it on a State that holds the complete account set (no Source).
+func (s *State) Root() (string, error) {
+	leaves, err := s.leaves()
+	if err != nil {
+		return "", err
+	}
+	root := subtreeRoot(leaves, 0)
+	return hex.EncodeToString(root[:]), nil
+}
+
+// leaves returns the tree leaves of all non-empty accounts, sorted by key
+func (s *State) leaves() ([]leaf, error) {
+	var leaves []leaf
+	for addr, acc := range s.accounts {
+		if acc.IsEmpty() {
+			continue
+		}
+		value, err := acc.Encode()
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf{key: treeKey(addr), value: value})
+	}
+	sortLeaves(leaves)
+	return leaves, nil
+}
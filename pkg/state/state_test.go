@@ -0,0 +1,261 @@
+package state
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/keys"
+)
+
+// testKey generates a sender key
+func testKey(t *testing.T) keys.PrivateKey {
+	t.Helper()
+	priv, err := keys.GenerateKey(keys.Ed25519)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return priv
+}
+
+// signedTx returns a signed transaction of the given version from priv.
+// Version 3 transactions carry nonce and fee, the others ignore them.
+func signedTx(t *testing.T, priv keys.PrivateKey, to string, amount, fee float64, nonce uint64, version int32) *blockchain.Transaction {
+	t.Helper()
+	var tx *blockchain.Transaction
+	if version == blockchain.TxVersion3 {
+		tx = blockchain.NewNoncedTransaction(priv.Public(), []byte(to), amount, fee, nonce)
+	} else {
+		tx = blockchain.NewTransaction(priv.Public(), []byte(to), amount)
+	}
+	if err := tx.Sign(priv); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return tx
+}
+
+// funded returns an account holding balance
+func funded(balance float64) *Account {
+	acc := NewAccount()
+	acc.Balance.SetFloat64(balance)
+	return acc
+}
+
+// fakeIndex is a TxIndex holding a fixed set of hashes
+type fakeIndex map[string]bool
+
+func (idx fakeIndex) HasTx(hash []byte) (bool, error) {
+	return idx[string(hash)], nil
+}
+
+func TestApplyTxRefusesReplay(t *testing.T) {
+	priv := testKey(t)
+	from := priv.Public().Address()
+
+	// Version 1 transactions carry no nonce, only their hash tells a replay apart
+	tx := signedTx(t, priv, "bob", 1, 0, 0, blockchain.TxVersion1)
+	st := New(nil)
+	st.Set(from, funded(10))
+	if err := st.ApplyTx(tx); err != nil {
+		t.Fatalf("ApplyTx: %v", err)
+	}
+	if err := st.ApplyTx(tx); !errors.Is(err, ErrDuplicateTx) {
+		t.Fatalf("second ApplyTx: want ErrDuplicateTx, got %v", err)
+	}
+	if err := st.Copy().ApplyTx(tx); !errors.Is(err, ErrDuplicateTx) {
+		t.Fatalf("ApplyTx on a copy: want ErrDuplicateTx, got %v", err)
+	}
+	if bal, _ := st.Get("bob"); bal.Balance.Text('f', 2) != "1.00" {
+		t.Errorf("receiver credited twice: %s", bal.Balance.Text('f', 2))
+	}
+
+	// A transaction included before the state was loaded is refused through the index
+	hash, _ := tx.Hash()
+	st = New(nil)
+	st.Set(from, funded(10))
+	st.UseTxIndex(fakeIndex{string(hash): true})
+	if err := st.ApplyTx(tx); !errors.Is(err, ErrDuplicateTx) {
+		t.Fatalf("indexed tx: want ErrDuplicateTx, got %v", err)
+	}
+	if len(st.Changed()) != 1 {
+		t.Errorf("refused tx changed the state: %v", st.Changed())
+	}
+}
+
+// singleLeafRoot computes the root of a tree holding one account by walking up
+// from its leaf, independently of subtreeRoot
+func singleLeafRoot(t *testing.T, address string, acc *Account) string {
+	t.Helper()
+	value, err := acc.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	key := treeKey(address)
+	node := leafHash(key, value)
+	for depth := treeDepth - 1; depth >= 0; depth-- {
+		if bitAt(key, depth) == 0 {
+			node = innerHash(node, emptyHash)
+		} else {
+			node = innerHash(emptyHash, node)
+		}
+	}
+	return hex.EncodeToString(node[:])
+}
+
+func root(t *testing.T, st *State) string {
+	t.Helper()
+	r, err := st.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	return r
+}
+
+func TestEmptyRoot(t *testing.T) {
+	want := strings.Repeat("0", 64)
+	if got := root(t, New(nil)); got != want {
+		t.Errorf("empty state: root %s, want %s", got, want)
+	}
+
+	// Empty accounts aren't stored in the tree
+	st := New(nil)
+	st.Set("alice", NewAccount())
+	if got := root(t, st); got != want {
+		t.Errorf("state of empty accounts: root %s, want %s", got, want)
+	}
+}
+
+func TestRootStable(t *testing.T) {
+	accounts := map[string]*Account{"alice": funded(1), "bob": funded(2.5), "carol": funded(0.00000001)}
+	order := [][]string{{"alice", "bob", "carol"}, {"carol", "alice", "bob"}, {"bob", "carol", "alice"}}
+
+	var first string
+	for _, addrs := range order {
+		st := New(nil)
+		for _, addr := range addrs {
+			st.Set(addr, accounts[addr].Copy())
+		}
+		r := root(t, st)
+		if r != root(t, st) {
+			t.Fatalf("root changed between calls")
+		}
+		if first == "" {
+			first = r
+		} else if r != first {
+			t.Errorf("order %v: root %s, want %s", addrs, r, first)
+		}
+	}
+
+	st := New(nil)
+	st.Set("alice", funded(1))
+	if got, want := root(t, st), singleLeafRoot(t, "alice", funded(1)); got != want {
+		t.Errorf("single account: root %s, want %s", got, want)
+	}
+}
+
+func TestRootInsertUpdateDelete(t *testing.T) {
+	st := New(nil)
+	st.Set("alice", funded(1))
+	one := root(t, st)
+
+	st.Set("bob", funded(2))
+	two := root(t, st)
+	if two == one {
+		t.Fatalf("inserting an account didn't change the root")
+	}
+
+	st.Set("bob", funded(3))
+	updated := root(t, st)
+	if updated == two {
+		t.Fatalf("updating an account didn't change the root")
+	}
+	st.Set("bob", funded(2))
+	if got := root(t, st); got != two {
+		t.Errorf("restoring the balance: root %s, want %s", got, two)
+	}
+
+	// An account emptied out leaves the tree
+	st.Set("bob", NewAccount())
+	if got := root(t, st); got != one {
+		t.Errorf("after deleting bob: root %s, want %s", got, one)
+	}
+	st.Set("alice", NewAccount())
+	if got := root(t, st); got != strings.Repeat("0", 64) {
+		t.Errorf("after deleting every account: root %s", got)
+	}
+}
+
+func TestApplyTxNonceAndFee(t *testing.T) {
+	priv := testKey(t)
+	from := priv.Public().Address()
+	v3 := blockchain.TxVersion3
+
+	balances := func(st *State) (string, uint64, string) {
+		sender, _ := st.Get(from)
+		receiver, _ := st.Get("bob")
+		return sender.Balance.Text('f', 8), sender.Nonce, receiver.Balance.Text('f', 8)
+	}
+
+	st := New(nil)
+	st.Set(from, funded(10))
+	if err := st.ApplyTx(signedTx(t, priv, "bob", 3, 0.5, 0, v3)); err != nil {
+		t.Fatalf("ApplyTx: %v", err)
+	}
+	// The fee is burned: the sender pays it, nobody receives it
+	if bal, nonce, recv := balances(st); bal != "6.50000000" || nonce != 1 || recv != "3.00000000" {
+		t.Fatalf("after the first tx: sender %s nonce %d, receiver %s", bal, nonce, recv)
+	}
+
+	tests := []struct {
+		name string
+		tx   *blockchain.Transaction
+		err  error
+	}{
+		{"reused nonce", signedTx(t, priv, "bob", 1, 0, 0, v3), ErrNonce},
+		{"skipped nonce", signedTx(t, priv, "bob", 1, 0, 2, v3), ErrNonce},
+		{"fee on top of the balance", signedTx(t, priv, "bob", 6, 0.6, 1, v3), ErrInsufficientBalance},
+		{"zero amount", signedTx(t, priv, "bob", 0, 0.1, 1, v3), ErrInvalidAmount},
+	}
+	for _, tc := range tests {
+		before := root(t, st)
+		if err := st.ApplyTx(tc.tx); !errors.Is(err, tc.err) {
+			t.Errorf("%s: want %v, got %v", tc.name, tc.err, err)
+		}
+		if got := root(t, st); got != before {
+			t.Errorf("%s: refused tx changed the state", tc.name)
+		}
+	}
+
+	// The whole balance can be spent on amount and fee
+	if err := st.ApplyTx(signedTx(t, priv, "bob", 6, 0.5, 1, v3)); err != nil {
+		t.Fatalf("ApplyTx spending everything: %v", err)
+	}
+	if bal, nonce, recv := balances(st); bal != "0.00000000" || nonce != 2 || recv != "9.00000000" {
+		t.Fatalf("after the second tx: sender %s nonce %d, receiver %s", bal, nonce, recv)
+	}
+
+	// Transactions without a nonce don't use it
+	st.Set(from, funded(1))
+	if err := st.ApplyTx(signedTx(t, priv, "bob", 1, 0, 0, blockchain.TxVersion1)); err != nil {
+		t.Fatalf("ApplyTx version 1: %v", err)
+	}
+	if _, nonce, _ := balances(st); nonce != 0 {
+		t.Errorf("version 1 tx set the nonce to %d", nonce)
+	}
+
+	// Sending to yourself only costs the fee
+	self := New(nil)
+	self.Set(from, funded(2))
+	tx := blockchain.NewNoncedTransaction(priv.Public(), []byte(from), 1, 0.25, 0)
+	if err := tx.Sign(priv); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := self.ApplyTx(tx); err != nil {
+		t.Fatalf("ApplyTx to self: %v", err)
+	}
+	if acc, _ := self.Get(from); acc.Balance.Text('f', 8) != "1.75000000" || acc.Nonce != 1 {
+		t.Errorf("self transfer: balance %s nonce %d", acc.Balance.Text('f', 8), acc.Nonce)
+	}
+}
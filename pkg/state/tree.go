@@ -0,0 +1,79 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+)
+
+// The state root is the root of a sparse Merkle tree with 2^256 leaves.
+// An account lives at the leaf addressed by SHA-256 of its address, walking
+// the key bits from the most significant one (0 = left, 1 = right).
+//
+// Empty subtrees hash to 32 zero bytes at every level, so only the paths
+// to existing accounts have to be hashed. Leaves and inner nodes use different
+// prefixes so a leaf can never be passed off as an inner node:
+//
+//	leaf  = SHA-256(0x00 || key || SHA-256(encoded account))
+//	inner = SHA-256(0x01 || left || right), or empty if both children are empty
+const treeDepth = 256
+
+var emptyHash [32]byte
+
+type leaf struct {
+	key   [32]byte
+	value []byte
+}
+
+// treeKey returns the position of an address in the tree
+func treeKey(address string) [32]byte {
+	return sha256.Sum256([]byte(address))
+}
+
+func leafHash(key [32]byte, value []byte) [32]byte {
+	valueHash := sha256.Sum256(value)
+	buf := make([]byte, 0, 1+32+32)
+	buf = append(buf, 0x00)
+	buf = append(buf, key[:]...)
+	buf = append(buf, valueHash[:]...)
+	return sha256.Sum256(buf)
+}
+
+func innerHash(left, right [32]byte) [32]byte {
+	if left == emptyHash && right == emptyHash {
+		return emptyHash
+	}
+	buf := make([]byte, 0, 1+32+32)
+	buf = append(buf, 0x01)
+	buf = append(buf, left[:]...)
+	buf = append(buf, right[:]...)
+	return sha256.Sum256(buf)
+}
+
+// bitAt returns the i-th bit of the key, counting from the most significant bit
+func bitAt(key [32]byte, i int) byte {
+	return (key[i/8] >> (7 - uint(i%8))) & 1
+}
+
+// sortLeaves orders leaves by key, which is the left-to-right order of the tree
+func sortLeaves(leaves []leaf) {
+	sort.Slice(leaves, func(i, j int) bool {
+		return bytes.Compare(leaves[i].key[:], leaves[j].key[:]) < 0
+	})
+}
+
+// subtreeRoot hashes the subtree at the given depth that holds exactly the sorted leaves
+func subtreeRoot(leaves []leaf, depth int) [32]byte {
+	if len(leaves) == 0 {
+		return emptyHash
+	}
+	if depth == treeDepth {
+		return leafHash(leaves[0].key, leaves[0].value)
+	}
+
+	// Leaves are sorted, so the ones going right follow the ones going left
+	split := sort.Search(len(leaves), func(i int) bool {
+		return bitAt(leaves[i].key, depth) == 1
+	})
+	return innerHash(subtreeRoot(leaves[:split], depth+1), subtreeRoot(leaves[split:], depth+1))
+}
@@ -1,33 +1,74 @@
 package storage
 
 import (
-	"encoding/json"
 	"math/big"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/state"
 )
 
-// Lưu số dư dưới dạng string để tránh mất độ chính xác khi marshal float
-func (d *DB) SetBalance(address string, amount *big.Float) error {
-	bytes, err := json.Marshal(amount.Text('f', 8)) // lưu dạng chuỗi
+// GetAccount returns the account stored at address, or an empty account if there is none
+func (d *DB) GetAccount(address string) (*state.Account, error) {
+	data, err := d.store.Get(accountKey(address))
+	if err == ErrNotFound {
+		return state.NewAccount(), nil
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return d.db.Put([]byte("balance_"+address), bytes, nil)
+	return state.DecodeAccount(data)
 }
 
 func (d *DB) GetBalance(address string) (*big.Float, error) {
-	data, err := d.db.Get([]byte("balance_"+address), nil)
+	acc, err := d.GetAccount(address)
 	if err != nil {
-		return big.NewFloat(0), nil
-	}
-
-	var str string
-	if err := json.Unmarshal(data, &str); err != nil {
 		return nil, err
 	}
+	return acc.Balance, nil
+}
 
-	val, _, err := big.ParseFloat(str, 10, 256, big.ToNearestEven)
+// LoadState reads every account into memory, e.g. to compute the state root.
+// The state refuses transactions that are already included in the chain.
+func (d *DB) LoadState() (*state.State, error) {
+	st := state.New(nil)
+	st.UseTxIndex(d)
+	var decodeErr error
+	err := d.store.Iterate(statePrefix, false, func(key, value []byte) bool {
+		var acc *state.Account
+		if acc, decodeErr = state.DecodeAccount(value); decodeErr != nil {
+			return false
+		}
+		st.Set(string(key[len(statePrefix):]), acc)
+		return true
+	})
+	if err == nil {
+		err = decodeErr
+	}
 	if err != nil {
 		return nil, err
 	}
-	return val, nil
+	return st, nil
+}
+
+// applyState executes the block's transactions against the stored accounts
+// and adds every account they changed to the batch.
+func (d *DB) applyState(batch *Batch, block *blockchain.Block) error {
+	st := state.New(d)
+	st.UseTxIndex(d)
+	if err := st.ApplyBlock(block); err != nil {
+		return err
+	}
+	return putAccounts(batch, st)
+}
+
+// putAccounts adds the accounts changed in st to the batch
+func putAccounts(batch *Batch, st *state.State) error {
+	for addr, acc := range st.Changed() {
+		data, err := acc.Encode()
+		if err != nil {
+			return err
+		}
+		batch.Put(accountKey(addr), data)
+	}
+	return nil
 }
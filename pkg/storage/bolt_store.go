@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltBucket is the single bucket holding all keys; namespacing is done by key prefixes
+var boltBucket = []byte("chain")
+
+// BoltStore is a Store backed by a bbolt file
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens or creates a bbolt database in the directory at path
+func OpenBoltStore(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(filepath.Join(path, "chain.db"), 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// OpenBoltStoreReadOnly opens an existing bbolt database in the directory at path without allowing writes
+func OpenBoltStoreReadOnly(path string) (*BoltStore, error) {
+	file := filepath.Join(path, "chain.db")
+	if _, err := os.Stat(file); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(file, 0600, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	err = db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltBucket) == nil {
+			return fmt.Errorf("%s holds no chain data", file)
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Get(key []byte) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(boltBucket).Get(key)
+		if v == nil {
+			return ErrNotFound
+		}
+		// bbolt values are only valid inside the transaction
+		value = append([]byte{}, v...)
+		return nil
+	})
+	return value, err
+}
+
+func (s *BoltStore) Has(key []byte) (bool, error) {
+	_, err := s.Get(key)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *BoltStore) Put(key, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put(key, value)
+	})
+}
+
+func (s *BoltStore) Delete(key []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete(key)
+	})
+}
+
+func (s *BoltStore) Write(batch *Batch) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltBucket)
+		for _, op := range batch.ops {
+			var err error
+			if op.delete {
+				err = b.Delete(op.key)
+			} else {
+				err = b.Put(op.key, op.value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) Iterate(prefix []byte, reverse bool, fn func(key, value []byte) bool) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+
+		if !reverse {
+			for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+				if !fn(k, v) {
+					break
+				}
+			}
+			return nil
+		}
+
+		// Position on the last key of the prefix range
+		var k, v []byte
+		if end := prefixEnd(prefix); end == nil {
+			k, v = c.Last()
+		} else if k, v = c.Seek(end); k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, v = c.Prev() {
+			if !fn(k, v) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
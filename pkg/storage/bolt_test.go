@@ -0,0 +1,18 @@
+package storage_test
+
+import (
+	"testing"
+
+	"golang-chain/pkg/storage"
+	"golang-chain/pkg/storage/storagetest"
+)
+
+func TestBoltStore(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Store {
+		s, err := storage.OpenBoltStore(t.TempDir())
+		if err != nil {
+			t.Fatalf("OpenBoltStore: %v", err)
+		}
+		return s
+	})
+}
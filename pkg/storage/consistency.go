@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// CheckConsistency inspects the database on startup for partial writes left behind
+// by a crash (or by versions that didn't write blocks atomically) and repairs what it can:
+// - blocks stored above the "latest" pointer are dropped so they get synced again,
+// - blocks whose transactions were never applied are replayed into the account state,
+// - without a recorded state height, the state and indexes are rebuilt from all blocks.
+// Damage that can't be repaired, like a missing block below the head, is returned as an error.
+func (d *DB) CheckConsistency() error {
+	latest, err := d.GetLatestBlock()
+	if err == ErrNotFound {
+		if _, err := d.store.Get(latestKey); err == nil {
+			return fmt.Errorf("latest pointer refers to a block that is not stored")
+		}
+		// Fresh database, or a crash before the first head update
+		return d.dropBlocksFrom(0)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read latest block: %w", err)
+	}
+
+	// Blocks above the head were written without moving the head pointer
+	if err := d.dropBlocksFrom(latest.Height + 1); err != nil {
+		return err
+	}
+
+	// Every block from the base (zero unless started from a snapshot) up to the head must be present,
+	// though only its header if the body was pruned
+	base, err := d.BaseHeight()
+	if err != nil {
+		return err
+	}
+	for h := base; h <= latest.Height; h++ {
+		if _, err := d.GetHeaderByHeight(h); err != nil {
+			return fmt.Errorf("block at height %d is missing: %w", h, err)
+		}
+	}
+
+	// An unfinished reindex rebuilds the state itself when it's resumed
+	if reindexing, err := d.Reindexing(); err != nil || reindexing {
+		return err
+	}
+
+	// The state must have been applied up to the head
+	stateHeight, err := d.getStateHeight()
+	if err == ErrNotFound {
+		// Nothing tells how far the stored state got, so derive it again from the blocks
+		log.Println("⚠️ No state height recorded, rebuilding balances and indexes from the stored blocks")
+		if err := d.Reindex(nil); err != nil {
+			return fmt.Errorf("no state height recorded and the state can't be rebuilt: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if stateHeight > latest.Height {
+		return fmt.Errorf("state applied up to height %d but latest block is at %d", stateHeight, latest.Height)
+	}
+
+	for h := stateHeight + 1; h <= latest.Height; h++ {
+		blk, err := d.GetBlockByHeight(h)
+		if err != nil {
+			return err
+		}
+		batch := new(Batch)
+		if err := d.applyBlock(batch, blk); err != nil {
+			return err
+		}
+		if err := d.store.Write(batch); err != nil {
+			return err
+		}
+		log.Printf("🔧 Replayed state of block at height %d", h)
+	}
+
+	return nil
+}
+
+// dropBlocksFrom deletes the consecutive blocks stored from the given height upwards.
+func (d *DB) dropBlocksFrom(height int64) error {
+	for h := height; ; h++ {
+		hash, err := d.store.Get(heightKey(h))
+		if err == ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		batch := new(Batch)
+		batch.Delete(headerKey(string(hash)))
+		batch.Delete(bodyKey(string(hash)))
+		batch.Delete(heightKey(h))
+		if err := d.store.Write(batch); err != nil {
+			return err
+		}
+		log.Printf("🔧 Dropped partially written block at height %d", h)
+	}
+}
+
+// getStateHeight returns the height of the last block applied to the account state.
+func (d *DB) getStateHeight() (int64, error) {
+	data, err := d.store.Get(stateHeightKey)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
@@ -0,0 +1,125 @@
+package storage_test
+
+import (
+	"strings"
+	"testing"
+
+	"golang-chain/pkg/state"
+	"golang-chain/pkg/storage"
+)
+
+// dump copies every entry of the store under the given key prefixes
+func dump(t *testing.T, s storage.Store, prefixes ...string) map[string]string {
+	t.Helper()
+	out := make(map[string]string)
+	for _, p := range prefixes {
+		err := s.Iterate([]byte(p), false, func(k, v []byte) bool {
+			out[string(k)] = string(v)
+			return true
+		})
+		if err != nil {
+			t.Fatalf("Iterate(%s): %v", p, err)
+		}
+	}
+	return out
+}
+
+// restore makes the entries under the prefixes what dump returned, as if later writes
+// to them never happened
+func restore(t *testing.T, s storage.Store, saved map[string]string, prefixes ...string) {
+	t.Helper()
+	batch := new(storage.Batch)
+	for k := range dump(t, s, prefixes...) {
+		batch.Delete([]byte(k))
+	}
+	for k, v := range saved {
+		batch.Put([]byte(k), []byte(v))
+	}
+	if err := s.Write(batch); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+}
+
+// derived are the key prefixes of the data the state transition writes
+var derived = []string{"s/", "i/", "m/state_height"}
+
+func TestCheckConsistencyTornWrite(t *testing.T) {
+	c := newTestChain(t, 10)
+	c.add(t, c.tx(t, "bob", 1))
+	afterOne := dump(t, c.store, derived...)
+
+	// The head moved to block 2 but its state changes never made it to disk
+	two := c.add(t, c.tx(t, "bob", 2))
+	want := dump(t, c.store, derived...)
+	restore(t, c.store, afterOne, derived...)
+
+	// Block 3 was stored without moving the head to it
+	headTwo := dump(t, c.store, "m/latest")
+	three := c.add(t, c.tx(t, "bob", 3))
+	restore(t, c.store, headTwo, "m/latest")
+	restore(t, c.store, afterOne, derived...)
+
+	if err := c.db.CheckConsistency(); err != nil {
+		t.Fatalf("CheckConsistency: %v", err)
+	}
+
+	if latest, err := c.db.GetLatestBlock(); err != nil || latest.CurrentBlockHash != two.CurrentBlockHash {
+		t.Fatalf("head after repair: got %+v, %v", latest, err)
+	}
+	if _, err := c.db.GetBlockByHeight(three.Height); err != storage.ErrNotFound {
+		t.Errorf("block above the head: want ErrNotFound, got %v", err)
+	}
+	if got := dump(t, c.store, derived...); !equalMaps(got, want) {
+		t.Errorf("state after replaying block 2:\n got %v\nwant %v", got, want)
+	}
+	if bal, _ := c.db.GetBalance("bob"); bal.Text('f', 2) != "3.00" {
+		t.Errorf("balance after repair: %s", bal.Text('f', 2))
+	}
+}
+
+func TestCheckConsistencyMissingStateHeight(t *testing.T) {
+	c := newTestChain(t, 0)
+	c.add(t)
+	c.add(t)
+
+	// An account no block created, written without recording how far the state got
+	ghost := state.NewAccount()
+	ghost.Balance.SetFloat64(5)
+	data, _ := ghost.Encode()
+	c.store.Put([]byte("s/ghost"), data)
+	c.store.Delete([]byte("m/state_height"))
+
+	if err := c.db.CheckConsistency(); err != nil {
+		t.Fatalf("CheckConsistency: %v", err)
+	}
+	if bal, _ := c.db.GetBalance("ghost"); bal.Sign() != 0 {
+		t.Errorf("state wasn't rebuilt from the blocks: ghost holds %s", bal.Text('f', 2))
+	}
+	if h, err := c.store.Get([]byte("m/state_height")); err != nil || string(h) != "2" {
+		t.Errorf("state height: got %q, %v", h, err)
+	}
+	if reindexing, _ := c.db.Reindexing(); reindexing {
+		t.Errorf("rebuild left the reindex marker behind")
+	}
+
+	// With pruned blocks the state can't be rebuilt, which is reported
+	if _, err := c.db.Prune(1); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	c.store.Delete([]byte("m/state_height"))
+	if err := c.db.CheckConsistency(); err == nil || !strings.Contains(err.Error(), "no state height") {
+		t.Fatalf("CheckConsistency with pruned blocks: got %v", err)
+	}
+}
+
+func equalMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
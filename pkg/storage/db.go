@@ -4,72 +4,181 @@ import (
 	"encoding/json"
 	"fmt"
 	"golang-chain/pkg/blockchain"
-
-	"github.com/syndtr/goleveldb/leveldb"
+	"strconv"
 )
 
-// DB wraps the LevelDB instance for blockchain storage access
+// DB provides blockchain storage access on top of a key-value Store
 type DB struct {
-	db *leveldb.DB
+	store            Store
+	snapshotInterval int64
 }
 
 // NewDB opens or creates a LevelDB database at the given path
 func NewDB(path string) (*DB, error) {
-	ldb, err := leveldb.OpenFile(path, nil)
+	return OpenDB(EngineLevelDB, path)
+}
+
+// OpenDB opens or creates a database using the given storage engine
+func OpenDB(engine, path string) (*DB, error) {
+	store, err := OpenStore(engine, path)
 	if err != nil {
 		return nil, err
 	}
-	return &DB{db: ldb}, nil
+	return NewDBWithStore(store), nil
 }
 
-// SaveBlock stores a block in the database
-// It saves the block under three keys:
-// - the block hash (for lookup by hash),
-// - the block height (for sequential access),
-// - and updates the "latest" pointer to this block
-func (d *DB) SaveBlock(block *blockchain.Block) error {
-	// Serialize the block to JSON
-	data, err := json.Marshal(block)
+// OpenDBReadOnly opens an existing database without allowing writes,
+// e.g. to inspect the data directory of a stopped node
+func OpenDBReadOnly(engine, path string) (*DB, error) {
+	store, err := OpenStoreReadOnly(engine, path)
 	if err != nil {
+		return nil, err
+	}
+	return NewDBWithStore(store), nil
+}
+
+// NewDBWithStore wraps an already opened store
+func NewDBWithStore(store Store) *DB {
+	return &DB{store: store}
+}
+
+// SaveBlock stores a block in the database and applies it to the chain state.
+// Everything is written in a single LevelDB batch so a crash can never leave
+// the node half way through a block. The batch contains:
+// - the block header and body, both keyed by the block hash,
+// - the height → hash index entry (for sequential access),
+// - the account history index entries and updated accounts,
+// - the state height marker and the "latest" pointer to this block
+// Saving a block that is already stored at its height is a no-op.
+// A state snapshot is taken afterwards if the block falls on the snapshot interval.
+func (d *DB) SaveBlock(block *blockchain.Block) error {
+	// Don't apply the same block twice, and never overwrite a different one
+	if existing, err := d.store.Get(heightKey(block.Height)); err == nil {
+		if string(existing) == block.CurrentBlockHash {
+			return nil
+		}
+		return fmt.Errorf("height %d already holds block %s", block.Height, existing)
+	} else if err != ErrNotFound {
 		return err
 	}
 
-	// Save block by hash
-	err = d.db.Put([]byte(block.CurrentBlockHash), data, nil)
-	if err != nil {
+	batch := new(Batch)
+	if err := putBlock(batch, block); err != nil {
 		return err
 	}
 
-	// Save block by height
-	heightKey := []byte(fmt.Sprintf("height_%d", block.Height))
-	if err := d.db.Put(heightKey, data, nil); err != nil {
+	if err := d.applyBlock(batch, block); err != nil {
 		return err
 	}
 
 	// Update latest block pointer
-	return d.db.Put([]byte("latest"), []byte(block.CurrentBlockHash), nil)
+	batch.Put(latestKey, []byte(block.CurrentBlockHash))
+
+	if err := d.store.Write(batch); err != nil {
+		return err
+	}
+	d.maybeSnapshot(block)
+	return nil
+}
+
+// putBlock adds the block's header, body and height index entry to the batch.
+func putBlock(batch *Batch, block *blockchain.Block) error {
+	if err := putBlockData(batch, block); err != nil {
+		return err
+	}
+	batch.Put(heightKey(block.Height), []byte(block.CurrentBlockHash))
+	return nil
+}
+
+// putBlockData adds only the block's header and body to the batch.
+func putBlockData(batch *Batch, block *blockchain.Block) error {
+	header, err := json.Marshal(block.Header())
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(block.Transactions)
+	if err != nil {
+		return err
+	}
+
+	batch.Put(headerKey(block.CurrentBlockHash), header)
+	batch.Put(bodyKey(block.CurrentBlockHash), body)
+	return nil
 }
 
-// GetBlock fetches a block by its hash
+// applyBlock adds the block's derived data to the batch: history index entries,
+// account changes and the height up to which the state has been applied.
+func (d *DB) applyBlock(batch *Batch, block *blockchain.Block) error {
+	if err := d.indexTransactions(batch, block); err != nil {
+		return err
+	}
+	if err := d.applyState(batch, block); err != nil {
+		return err
+	}
+	batch.Put(stateHeightKey, []byte(strconv.FormatInt(block.Height, 10)))
+	return nil
+}
+
+// GetBlock fetches a block by its hash.
+// It returns ErrPruned if only the header of the block is still stored.
 func (d *DB) GetBlock(hash []byte) (*blockchain.Block, error) {
-	data, err := d.db.Get(hash, nil)
+	header, err := d.GetHeader(string(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := d.store.Get(bodyKey(string(hash)))
+	if err == ErrNotFound {
+		return nil, ErrPruned
+	}
 	if err != nil {
 		return nil, err
 	}
-	var block blockchain.Block
-	if err := json.Unmarshal(data, &block); err != nil {
+	var txs []*blockchain.Transaction
+	if err := json.Unmarshal(data, &txs); err != nil {
 		return nil, err
 	}
-	return &block, nil
+	return blockchain.AssembleBlock(header, txs), nil
+}
+
+// GetHeader fetches only the header of a block by its hash
+func (d *DB) GetHeader(hash string) (*blockchain.BlockHeader, error) {
+	data, err := d.store.Get(headerKey(hash))
+	if err != nil {
+		return nil, err
+	}
+	var header blockchain.BlockHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+// GetHeaderByHeight fetches the header of the block at the given height.
+// Unlike the full block it's kept even when the node prunes old blocks.
+func (d *DB) GetHeaderByHeight(height int64) (*blockchain.BlockHeader, error) {
+	hash, err := d.store.Get(heightKey(height))
+	if err != nil {
+		return nil, err
+	}
+	return d.GetHeader(string(hash))
+}
+
+// deletePrefix adds the deletion of every key starting with prefix to the batch
+func (d *DB) deletePrefix(batch *Batch, prefix []byte) error {
+	return d.store.Iterate(prefix, false, func(key, _ []byte) bool {
+		batch.Delete(key)
+		return true
+	})
 }
 
 func (d *DB) Close() {
-	d.db.Close()
+	d.store.Close()
 }
 
 // GetLatestBlock returns the latest block based on the stored "latest" pointer
 func (db *DB) GetLatestBlock() (*blockchain.Block, error) {
-	hashBytes, err := db.db.Get([]byte("latest"), nil)
+	hashBytes, err := db.store.Get(latestKey)
 	if err != nil {
 		return nil, err
 	}
@@ -78,13 +187,9 @@ func (db *DB) GetLatestBlock() (*blockchain.Block, error) {
 
 // GetBlockByHeight fetches a block by its height in the chain
 func (d *DB) GetBlockByHeight(height int64) (*blockchain.Block, error) {
-	data, err := d.db.Get([]byte(fmt.Sprintf("height_%d", height)), nil)
+	hash, err := d.store.Get(heightKey(height))
 	if err != nil {
 		return nil, err
 	}
-	var blk blockchain.Block
-	if err := json.Unmarshal(data, &blk); err != nil {
-		return nil, err
-	}
-	return &blk, nil
+	return d.GetBlock(hash)
 }
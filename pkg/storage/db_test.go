@@ -0,0 +1,208 @@
+package storage_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/keys"
+	"golang-chain/pkg/state"
+	"golang-chain/pkg/storage"
+)
+
+// testChain is a memory-backed DB with a funded sender
+type testChain struct {
+	store  *storage.MemoryStore
+	db     *storage.DB
+	sender keys.PrivateKey
+	st     *state.State // state after the last block built by next
+	blocks []*blockchain.Block
+}
+
+// newTestChain creates a DB holding only the genesis block, and a sender funded with balance.
+// The chain doesn't issue coins, so the account is put directly under its "s/<address>" state key.
+func newTestChain(t *testing.T, balance float64) *testChain {
+	t.Helper()
+	sender, err := keys.GenerateKey(keys.Ed25519)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	c := &testChain{store: storage.NewMemoryStore(), sender: sender, st: state.New(nil)}
+	c.db = storage.NewDBWithStore(c.store)
+
+	acc := state.NewAccount()
+	acc.Balance.SetFloat64(balance)
+	data, err := acc.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := c.store.Put([]byte("s/"+sender.Public().Address()), data); err != nil {
+		t.Fatalf("Put(sender account): %v", err)
+	}
+	c.st.Set(sender.Public().Address(), acc)
+
+	genesis := blockchain.CreateGenesisBlock()
+	if err := c.db.SaveBlock(genesis); err != nil {
+		t.Fatalf("SaveBlock(genesis): %v", err)
+	}
+	c.blocks = []*blockchain.Block{genesis}
+	return c
+}
+
+// tx returns a signed version 1 transfer from the chain's sender
+func (c *testChain) tx(t *testing.T, to string, amount float64) *blockchain.Transaction {
+	t.Helper()
+	tx := blockchain.NewTransaction(c.sender.Public(), []byte(to), amount)
+	if err := tx.Sign(c.sender); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return tx
+}
+
+// next builds the block on top of the chain holding txs, with the state root they lead to
+func (c *testChain) next(t *testing.T, txs ...*blockchain.Transaction) *blockchain.Block {
+	t.Helper()
+	st := c.st.Copy()
+	for _, tx := range txs {
+		if err := st.ApplyTx(tx); err != nil {
+			t.Fatalf("ApplyTx: %v", err)
+		}
+	}
+	root, err := st.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	prev := c.blocks[len(c.blocks)-1]
+	block := blockchain.NewBlock(txs, prev.CurrentBlockHash, prev.Height+1, root)
+	c.st = st
+	c.blocks = append(c.blocks, block)
+	return block
+}
+
+// add builds the next block and saves it
+func (c *testChain) add(t *testing.T, txs ...*blockchain.Transaction) *blockchain.Block {
+	t.Helper()
+	block := c.next(t, txs...)
+	if err := c.db.SaveBlock(block); err != nil {
+		t.Fatalf("SaveBlock(%d): %v", block.Height, err)
+	}
+	return block
+}
+
+func TestMigrateEmptyStore(t *testing.T) {
+	db := storage.NewDBWithStore(storage.NewMemoryStore())
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate on empty store: %v", err)
+	}
+	if v, err := db.StoredSchemaVersion(); err != nil || v != storage.SchemaVersion {
+		t.Fatalf("schema version: got %d, %v", v, err)
+	}
+}
+
+func TestChainRoundTrip(t *testing.T) {
+	c := newTestChain(t, 10)
+	sender := c.sender.Public().Address()
+	tx := c.tx(t, "bob-address", 2.5)
+	block := c.add(t, tx)
+	root := block.StateRoot
+
+	// Saving the same block again is a no-op
+	if err := c.db.SaveBlock(block); err != nil {
+		t.Fatalf("SaveBlock again: %v", err)
+	}
+	if err := c.db.CheckConsistency(); err != nil {
+		t.Fatalf("CheckConsistency: %v", err)
+	}
+
+	latest, err := c.db.GetLatestBlock()
+	if err != nil || latest.CurrentBlockHash != block.CurrentBlockHash {
+		t.Fatalf("GetLatestBlock: got %+v, %v", latest, err)
+	}
+	if blockchain.HashBlock(latest) != block.CurrentBlockHash {
+		t.Fatalf("stored block doesn't hash back to %s", block.CurrentBlockHash)
+	}
+	byHeight, err := c.db.GetBlockByHeight(0)
+	if err != nil || byHeight.CurrentBlockHash != c.blocks[0].CurrentBlockHash {
+		t.Fatalf("GetBlockByHeight(0): got %+v, %v", byHeight, err)
+	}
+
+	bal, err := c.db.GetBalance("bob-address")
+	if err != nil || bal.Text('f', 2) != "2.50" {
+		t.Fatalf("balance applied once: got %v, %v", bal, err)
+	}
+	acc, err := c.db.GetAccount(sender)
+	if err != nil || acc.Nonce != 0 || acc.Balance.Text('f', 2) != "7.50" {
+		t.Fatalf("sender account: got %+v, %v", acc, err)
+	}
+
+	// A transaction the sender can't pay for is rejected, and leaves the stored state alone
+	overdraft := c.tx(t, "bob-address", 100)
+	next := blockchain.NewBlock([]*blockchain.Transaction{overdraft}, block.CurrentBlockHash, 2, root)
+	if err := c.db.SaveBlock(next); !errors.Is(err, state.ErrInsufficientBalance) {
+		t.Fatalf("SaveBlock of a block overdrawing the sender: want ErrInsufficientBalance, got %v", err)
+	}
+	if bal, _ := c.db.GetBalance("bob-address"); bal.Text('f', 2) != "2.50" {
+		t.Fatalf("balance after rejected block: got %v", bal)
+	}
+
+	loaded, err := c.db.LoadState()
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if got, _ := loaded.Root(); got != root {
+		t.Fatalf("stored state root: want %s, got %s", root, got)
+	}
+
+	locs, total, err := c.db.GetAccountHistory("bob-address", 0, 10)
+	if err != nil || total != 1 || len(locs) != 1 || locs[0].Height != 1 {
+		t.Fatalf("GetAccountHistory: got %v, %d, %v", locs, total, err)
+	}
+
+	txHash, _ := tx.Hash()
+	loc, err := c.db.GetTxLocation(hex.EncodeToString(txHash))
+	if err != nil || loc.Height != 1 || loc.Index != 0 {
+		t.Fatalf("GetTxLocation: got %+v, %v", loc, err)
+	}
+	if _, err := c.db.GetTxLocation("00"); err != storage.ErrNotFound {
+		t.Fatalf("GetTxLocation on unknown hash: want ErrNotFound, got %v", err)
+	}
+}
+
+func TestSaveBlockRefusesIncludedTx(t *testing.T) {
+	c := newTestChain(t, 10)
+	tx := c.tx(t, "bob", 1)
+	c.add(t, tx)
+
+	hash, _ := tx.Hash()
+	if ok, err := c.db.HasTx(hash); err != nil || !ok {
+		t.Fatalf("HasTx after inclusion: got %v, %v", ok, err)
+	}
+
+	// A block replaying the transaction can't be saved
+	prev := c.blocks[len(c.blocks)-1]
+	replay := blockchain.NewBlock([]*blockchain.Transaction{tx}, prev.CurrentBlockHash, prev.Height+1, "")
+	if err := c.db.SaveBlock(replay); !errors.Is(err, state.ErrDuplicateTx) {
+		t.Fatalf("SaveBlock(replay): want ErrDuplicateTx, got %v", err)
+	}
+	if bal, _ := c.db.GetBalance("bob"); bal.Text('f', 2) != "1.00" {
+		t.Errorf("receiver balance %s after refused replay, want 1.00", bal.Text('f', 2))
+	}
+
+	st, err := c.db.LoadState()
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if err := st.ApplyTx(tx); !errors.Is(err, state.ErrDuplicateTx) {
+		t.Fatalf("ApplyTx on the loaded state: want ErrDuplicateTx, got %v", err)
+	}
+
+	// Pruning the block keeps the transaction known
+	c.add(t)
+	if n, err := c.db.Prune(1); err != nil || n != 2 {
+		t.Fatalf("Prune: got %d, %v", n, err)
+	}
+	if ok, err := c.db.HasTx(hash); err != nil || !ok {
+		t.Fatalf("HasTx after pruning: got %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/state"
+)
+
+// TxLocation points at a transaction inside the chain: the height of the block
+// that contains it and its position in that block's transaction list
+type TxLocation struct {
+	Height int64
+	Index  int
+}
+
+// indexTransactions adds the index entries of every transaction in the block:
+// its location under its hash, and a history entry under both its sender and receiver address.
+func (d *DB) indexTransactions(batch *Batch, block *blockchain.Block) error {
+	for i, tx := range block.Transactions {
+		loc := TxLocation{Height: block.Height, Index: i}
+		data, err := json.Marshal(loc)
+		if err != nil {
+			return err
+		}
+
+		hash, err := tx.Hash()
+		if err != nil {
+			return err
+		}
+		batch.Put(txKey(hex.EncodeToString(hash)), data)
+
+		sender, receiver, err := state.TxAccounts(tx)
+		if err != nil {
+			return err
+		}
+
+		batch.Put(historyKey(sender, loc), data)
+		if receiver != sender {
+			batch.Put(historyKey(receiver, loc), data)
+		}
+	}
+	return nil
+}
+
+// unindexHistory removes the history entries added by indexTransactions for the block.
+// The transaction index entries stay, so transactions of pruned blocks are still
+// known to be included and can't be applied again.
+func (d *DB) unindexHistory(batch *Batch, block *blockchain.Block) error {
+	for i, tx := range block.Transactions {
+		loc := TxLocation{Height: block.Height, Index: i}
+
+		sender, receiver, err := state.TxAccounts(tx)
+		if err != nil {
+			return err
+		}
+		batch.Delete(historyKey(sender, loc))
+		batch.Delete(historyKey(receiver, loc))
+	}
+	return nil
+}
+
+// GetAccountHistory returns the locations of an account's transactions, newest first.
+// It skips the first offset entries and returns at most limit of them,
+// together with the total number of entries recorded for the account.
+// Only transactions in blocks whose body is still stored are listed and counted.
+func (d *DB) GetAccountHistory(account string, offset, limit int) ([]TxLocation, int, error) {
+	bodiesFrom, err := d.prunedHeight()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var locs []TxLocation
+	total := 0
+	var decodeErr error
+	err = d.store.Iterate(accountHistoryPrefix(account), true, func(_, value []byte) bool {
+		var loc TxLocation
+		if decodeErr = json.Unmarshal(value, &loc); decodeErr != nil {
+			return false
+		}
+		if loc.Height < bodiesFrom {
+			return true
+		}
+		if total >= offset && len(locs) < limit {
+			locs = append(locs, loc)
+		}
+		total++
+		return true
+	})
+	if err == nil {
+		err = decodeErr
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return locs, total, nil
+}
+
+// GetTxLocation looks up where the transaction with the given hex hash was included
+func (d *DB) GetTxLocation(txHash string) (*TxLocation, error) {
+	data, err := d.store.Get(txKey(txHash))
+	if err != nil {
+		return nil, err
+	}
+	var loc TxLocation
+	if err := json.Unmarshal(data, &loc); err != nil {
+		return nil, err
+	}
+	return &loc, nil
+}
+
+// HasTx reports whether the transaction with the given hash is included in the chain
+func (d *DB) HasTx(hash []byte) (bool, error) {
+	return d.store.Has(txKey(hex.EncodeToString(hash)))
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// Key schema. Every key starts with a one-letter namespace:
+//
+//	m/<name>                      metadata (schema version, head pointer, state height, base and pruned height, reindex marker)
+//	h/<hash>                      block header
+//	b/<hash>                      block body (the transaction list)
+//	n/<height>                    height → hash index, height zero-padded to 20 digits
+//	s/<address>                   account state (balance and nonce)
+//	i/history/<hex account>/...   account history index
+//	i/tx/<tx hash>                transaction location index
+//	p/m/<height>                  state snapshot manifest
+//	p/c/<height>/<index>          state snapshot chunk
+var (
+	versionKey     = []byte("m/version")
+	latestKey      = []byte("m/latest")
+	stateHeightKey = []byte("m/state_height")
+	baseKey        = []byte("m/base")
+	prunedKey      = []byte("m/pruned")
+	reindexKey     = []byte("m/reindex")
+
+	headerPrefix  = []byte("h/")
+	bodyPrefix    = []byte("b/")
+	heightPrefix  = []byte("n/")
+	statePrefix   = []byte("s/")
+	historyPrefix = []byte("i/history/")
+	txPrefix      = []byte("i/tx/")
+
+	snapshotManifestPrefix = []byte("p/m/")
+	snapshotChunkPrefix    = []byte("p/c/")
+)
+
+func headerKey(hash string) []byte {
+	return append(append([]byte{}, headerPrefix...), hash...)
+}
+
+func bodyKey(hash string) []byte {
+	return append(append([]byte{}, bodyPrefix...), hash...)
+}
+
+// heightKey zero-pads the height so LevelDB's byte ordering matches chain order.
+func heightKey(height int64) []byte {
+	return append(append([]byte{}, heightPrefix...), fmt.Sprintf("%020d", height)...)
+}
+
+func accountKey(account string) []byte {
+	return append(append([]byte{}, statePrefix...), account...)
+}
+
+// accountHistoryPrefix returns the key prefix under which all history entries of an account live.
+// The account is hex-encoded so that names containing "/" can't collide with each other.
+func accountHistoryPrefix(account string) []byte {
+	return append(append([]byte{}, historyPrefix...), hex.EncodeToString([]byte(account))+"/"...)
+}
+
+// historyKey builds the index key for one transaction of an account.
+// Height and index are zero-padded so LevelDB's byte ordering matches chain order.
+func historyKey(account string, loc TxLocation) []byte {
+	return append(accountHistoryPrefix(account), fmt.Sprintf("%020d_%06d", loc.Height, loc.Index)...)
+}
+
+func txKey(txHash string) []byte {
+	return append(append([]byte{}, txPrefix...), txHash...)
+}
+
+func snapshotManifestKey(height int64) []byte {
+	return append(append([]byte{}, snapshotManifestPrefix...), fmt.Sprintf("%020d", height)...)
+}
+
+// snapshotChunksPrefix returns the key prefix under which all chunks of a snapshot live
+func snapshotChunksPrefix(height int64) []byte {
+	return append(append([]byte{}, snapshotChunkPrefix...), fmt.Sprintf("%020d/", height)...)
+}
+
+func snapshotChunkKey(height int64, index int) []byte {
+	return append(snapshotChunksPrefix(height), fmt.Sprintf("%06d", index)...)
+}
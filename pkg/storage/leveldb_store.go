@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// LevelDBStore is a Store backed by goleveldb
+type LevelDBStore struct {
+	db *leveldb.DB
+}
+
+// OpenLevelDBStore opens or creates a LevelDB database at the given path
+func OpenLevelDBStore(path string) (*LevelDBStore, error) {
+	ldb, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBStore{db: ldb}, nil
+}
+
+// OpenLevelDBStoreReadOnly opens an existing LevelDB database without allowing writes
+func OpenLevelDBStoreReadOnly(path string) (*LevelDBStore, error) {
+	ldb, err := leveldb.OpenFile(path, &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBStore{db: ldb}, nil
+}
+
+func (s *LevelDBStore) Get(key []byte) ([]byte, error) {
+	data, err := s.db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *LevelDBStore) Has(key []byte) (bool, error) {
+	return s.db.Has(key, nil)
+}
+
+func (s *LevelDBStore) Put(key, value []byte) error {
+	return s.db.Put(key, value, nil)
+}
+
+func (s *LevelDBStore) Delete(key []byte) error {
+	return s.db.Delete(key, nil)
+}
+
+func (s *LevelDBStore) Write(batch *Batch) error {
+	lb := new(leveldb.Batch)
+	for _, op := range batch.ops {
+		if op.delete {
+			lb.Delete(op.key)
+		} else {
+			lb.Put(op.key, op.value)
+		}
+	}
+	return s.db.Write(lb, &opt.WriteOptions{Sync: true})
+}
+
+func (s *LevelDBStore) Iterate(prefix []byte, reverse bool, fn func(key, value []byte) bool) error {
+	iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	if reverse {
+		for ok := iter.Last(); ok; ok = iter.Prev() {
+			if !fn(iter.Key(), iter.Value()) {
+				break
+			}
+		}
+	} else {
+		for iter.Next() {
+			if !fn(iter.Key(), iter.Value()) {
+				break
+			}
+		}
+	}
+	return iter.Error()
+}
+
+// Compact rewrites the whole key range, dropping deleted entries from disk
+func (s *LevelDBStore) Compact() error {
+	return s.db.CompactRange(util.Range{})
+}
+
+func (s *LevelDBStore) Close() error {
+	return s.db.Close()
+}
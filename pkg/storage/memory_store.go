@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is a Store that keeps everything in a map.
+// Nothing survives Close; it's meant for tests and dev mode.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Get(key []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.data[string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte{}, value...), nil
+}
+
+func (s *MemoryStore) Has(key []byte) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.data[string(key)]
+	return ok, nil
+}
+
+func (s *MemoryStore) Put(key, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (s *MemoryStore) Delete(key []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, string(key))
+	return nil
+}
+
+func (s *MemoryStore) Write(batch *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Batch already holds private copies of keys and values
+	for _, op := range batch.ops {
+		if op.delete {
+			delete(s.data, string(op.key))
+		} else {
+			s.data[string(op.key)] = op.value
+		}
+	}
+	return nil
+}
+
+// Iterate works on a snapshot of the matching entries,
+// so fn may write to the store without deadlocking.
+func (s *MemoryStore) Iterate(prefix []byte, reverse bool, fn func(key, value []byte) bool) error {
+	s.mu.RLock()
+	var keys []string
+	for k := range s.data {
+		if strings.HasPrefix(k, string(prefix)) {
+			keys = append(keys, k)
+		}
+	}
+	values := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		values[k] = s.data[k]
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		less := bytes.Compare([]byte(keys[i]), []byte(keys[j])) < 0
+		if reverse {
+			return !less
+		}
+		return less
+	})
+
+	for _, k := range keys {
+		if !fn([]byte(k), values[k]) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
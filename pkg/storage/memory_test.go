@@ -0,0 +1,14 @@
+package storage_test
+
+import (
+	"testing"
+
+	"golang-chain/pkg/storage"
+	"golang-chain/pkg/storage/storagetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Store {
+		return storage.NewMemoryStore()
+	})
+}
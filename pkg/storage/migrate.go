@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/state"
+)
+
+// SchemaVersion is the storage format written by this version of the node.
+// Version 1 is the original flat keyspace that didn't record a version at all.
+const SchemaVersion = 4
+
+// migration upgrades the database from one schema version to the next.
+// It adds all of its changes to the batch, which is committed together with the new version.
+type migration struct {
+	from        int
+	description string
+	run         func(d *DB, batch *Batch) error
+}
+
+// migrations must be kept in order, one per version step
+var migrations = []migration{
+	{from: 1, description: "move flat keys into namespaces", run: migrateFlatKeys},
+	{from: 2, description: "rebuild account state keyed by address", run: rebuildDerivedState},
+	{from: 3, description: "index transactions by hash", run: rebuildDerivedState},
+}
+
+// StoredSchemaVersion returns the schema version recorded in the database.
+// A database without a version is 0 if it's empty and 1 (the legacy flat layout) otherwise.
+func (d *DB) StoredSchemaVersion() (int, error) {
+	data, err := d.store.Get(versionKey)
+	if err == nil {
+		return strconv.Atoi(string(data))
+	}
+	if err != ErrNotFound {
+		return 0, err
+	}
+
+	empty := true
+	err = d.store.Iterate(nil, false, func(_, _ []byte) bool {
+		empty = false
+		return false
+	})
+	if err != nil || empty {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Migrate upgrades the database in place to SchemaVersion, one version at a time.
+// Each step is written atomically together with its version number,
+// so an interrupted upgrade resumes from the last completed step.
+func (d *DB) Migrate() error {
+	version, err := d.StoredSchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		return d.store.Put(versionKey, []byte(strconv.Itoa(SchemaVersion)))
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, SchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.from != version {
+			continue
+		}
+		log.Printf("🔧 Migrating database from schema version %d to %d: %s", m.from, m.from+1, m.description)
+
+		batch := new(Batch)
+		if err := m.run(d, batch); err != nil {
+			return fmt.Errorf("migration from version %d failed: %w", m.from, err)
+		}
+		batch.Put(versionKey, []byte(strconv.Itoa(m.from+1)))
+		if err := d.store.Write(batch); err != nil {
+			return err
+		}
+		version = m.from + 1
+	}
+
+	if version != SchemaVersion {
+		return fmt.Errorf("no migration path from schema version %d", version)
+	}
+	return nil
+}
+
+// migrateFlatKeys moves the version 1 keys into the namespaced schema:
+// - "<hash>" and "height_N" full block copies become a header, a body and a height index entry,
+// - "latest" and "state_height" become metadata,
+// - "balance_<account>" becomes account state,
+// - "history_<hex account>_<height>_<index>" moves under the index namespace.
+func migrateFlatKeys(d *DB, batch *Batch) error {
+	// Copy all entries out first, keys and values are only valid inside the callback
+	type entry struct{ key, value []byte }
+	var entries []entry
+	err := d.store.Iterate(nil, false, func(key, value []byte) bool {
+		entries = append(entries, entry{append([]byte{}, key...), append([]byte{}, value...)})
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		key := string(e.key)
+		value := e.value
+
+		switch {
+		case key == "latest":
+			batch.Put(latestKey, value)
+		case key == "state_height":
+			batch.Put(stateHeightKey, value)
+		case strings.HasPrefix(key, "balance_"):
+			batch.Put(accountKey(strings.TrimPrefix(key, "balance_")), value)
+		case strings.HasPrefix(key, "history_"):
+			hexAccount, rest, ok := strings.Cut(strings.TrimPrefix(key, "history_"), "_")
+			if !ok {
+				return fmt.Errorf("malformed history key %q", key)
+			}
+			batch.Put([]byte(string(historyPrefix)+hexAccount+"/"+rest), value)
+		case strings.HasPrefix(key, "height_"):
+			var blk blockchain.Block
+			if err := json.Unmarshal(value, &blk); err != nil {
+				return fmt.Errorf("block at %q: %w", key, err)
+			}
+			if err := putBlock(batch, &blk); err != nil {
+				return err
+			}
+		default:
+			// Anything else should be a block stored under its own hash
+			var blk blockchain.Block
+			if err := json.Unmarshal(value, &blk); err != nil || blk.CurrentBlockHash != key {
+				log.Printf("⚠️ Leaving unknown key %q in place", key)
+				continue
+			}
+			if err := putBlockData(batch, &blk); err != nil {
+				return err
+			}
+		}
+
+		batch.Delete(e.key)
+	}
+	return nil
+}
+
+// rebuildDerivedState drops the account state and transaction indexes and derives them again
+// by replaying every stored block. Version 2 keyed accounts by wallet name
+// and didn't track nonces, version 3 had no transaction hash index.
+func rebuildDerivedState(d *DB, batch *Batch) error {
+	for _, prefix := range [][]byte{statePrefix, historyPrefix, txPrefix} {
+		if err := d.deletePrefix(batch, prefix); err != nil {
+			return err
+		}
+	}
+
+	latest, err := d.GetLatestBlock()
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	st := state.New(nil)
+	for h := int64(0); h <= latest.Height; h++ {
+		blk, err := d.GetBlockByHeight(h)
+		if err != nil {
+			return fmt.Errorf("block at height %d: %w", h, err)
+		}
+		if err := st.ApplyBlock(blk); err != nil {
+			return fmt.Errorf("block at height %d: %w", h, err)
+		}
+		if err := d.indexTransactions(batch, blk); err != nil {
+			return err
+		}
+	}
+	if err := putAccounts(batch, st); err != nil {
+		return err
+	}
+	batch.Put(stateHeightKey, []byte(strconv.FormatInt(latest.Height, 10)))
+	return nil
+}
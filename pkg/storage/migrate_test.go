@@ -0,0 +1,137 @@
+package storage_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/keys"
+	"golang-chain/pkg/state"
+	"golang-chain/pkg/storage"
+)
+
+// legacyStore returns a store in the original flat layout, without a schema version:
+// every block under its hash and under "height_N", "latest" and "state_height",
+// balances under "balance_<wallet name>" and history under "history_<hex account>_<height>_<index>"
+func legacyStore(t *testing.T, blocks []*blockchain.Block, extra map[string]string) *storage.MemoryStore {
+	t.Helper()
+	s := storage.NewMemoryStore()
+	put := func(key string, value []byte) {
+		if err := s.Put([]byte(key), value); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+	for _, b := range blocks {
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		put(b.CurrentBlockHash, data)
+		put("height_"+strconv.FormatInt(b.Height, 10), data)
+	}
+	latest := blocks[len(blocks)-1]
+	put("latest", []byte(latest.CurrentBlockHash))
+	put("state_height", []byte(strconv.FormatInt(latest.Height, 10)))
+	for k, v := range extra {
+		put(k, []byte(v))
+	}
+	return s
+}
+
+// legacyBlock builds a block the way the original node did: version 0, no state root
+func legacyBlock(prev *blockchain.Block, txs []*blockchain.Transaction) *blockchain.Block {
+	b := &blockchain.Block{
+		Transactions:  txs,
+		PrevBlockHash: prev.CurrentBlockHash,
+		MerkleRoot:    blockchain.CalculateMerkleRoot(txs, 0),
+		Height:        prev.Height + 1,
+	}
+	b.CurrentBlockHash = blockchain.HashBlock(b)
+	return b
+}
+
+func TestMigrateLegacyLayout(t *testing.T) {
+	genesis := blockchain.CreateGenesisBlock()
+	s := legacyStore(t, []*blockchain.Block{genesis}, map[string]string{
+		// Balances keyed by wallet name, which nothing backs: the rebuild drops them
+		"balance_Alice": `"100.00000000"`,
+		"history_416c696365_00000000000000000001_000000": `{"Height":1,"Index":0}`,
+		"unrelated": "left alone",
+	})
+	db := storage.NewDBWithStore(s)
+
+	if v, err := db.StoredSchemaVersion(); err != nil || v != 1 {
+		t.Fatalf("legacy schema version: got %d, %v", v, err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if v, err := db.StoredSchemaVersion(); err != nil || v != storage.SchemaVersion {
+		t.Fatalf("schema version after migrating: got %d, %v, want %d", v, err, storage.SchemaVersion)
+	}
+
+	// The blocks moved into their namespaces and the flat copies are gone
+	latest, err := db.GetLatestBlock()
+	if err != nil || latest.CurrentBlockHash != genesis.CurrentBlockHash {
+		t.Fatalf("GetLatestBlock: got %+v, %v", latest, err)
+	}
+	if b, err := db.GetBlockByHeight(0); err != nil || b.CurrentBlockHash != genesis.CurrentBlockHash {
+		t.Fatalf("GetBlockByHeight(0): got %+v, %v", b, err)
+	}
+	for _, key := range []string{genesis.CurrentBlockHash, "height_0", "latest", "state_height", "balance_Alice"} {
+		if ok, _ := s.Has([]byte(key)); ok {
+			t.Errorf("legacy key %q still present", key)
+		}
+	}
+	if v, err := s.Get([]byte("unrelated")); err != nil || string(v) != "left alone" {
+		t.Errorf("unknown key: got %q, %v", v, err)
+	}
+
+	// The account state and indexes were rebuilt from the blocks: the name-keyed balance
+	// and the history entry no block backs are gone
+	if bal, _ := db.GetBalance("Alice"); bal.Sign() != 0 {
+		t.Errorf("balance_Alice survived as %s", bal.Text('f', 8))
+	}
+	if _, total, err := db.GetAccountHistory("Alice", 0, 10); err != nil || total != 0 {
+		t.Errorf("history of Alice: %d entries, %v", total, err)
+	}
+	if h, err := s.Get([]byte("m/state_height")); err != nil || string(h) != "0" {
+		t.Errorf("state height: got %q, %v", h, err)
+	}
+	if err := db.CheckConsistency(); err != nil {
+		t.Errorf("CheckConsistency: %v", err)
+	}
+
+	// Migrating again is a no-op
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+}
+
+func TestMigrateLegacyUnfundedTransfer(t *testing.T) {
+	// The original node kept balances outside the blocks, so a legacy transfer may
+	// spend coins no block gave the sender. Replaying it fails instead of inventing
+	// the balance, and the steps done so far stay committed.
+	sender, err := keys.GenerateKey(keys.Ed25519)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tx := blockchain.NewTransaction(sender.Public(), []byte("bob"), 5)
+	if err := tx.Sign(sender); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	genesis := blockchain.CreateGenesisBlock()
+	s := legacyStore(t, []*blockchain.Block{genesis, legacyBlock(genesis, []*blockchain.Transaction{tx})}, nil)
+	db := storage.NewDBWithStore(s)
+
+	err = db.Migrate()
+	if !errors.Is(err, state.ErrInsufficientBalance) || !strings.Contains(err.Error(), "version 2") {
+		t.Fatalf("Migrate: want an insufficient balance error from version 2, got %v", err)
+	}
+	if v, err := db.StoredSchemaVersion(); err != nil || v != 2 {
+		t.Fatalf("schema version after the failed step: got %d, %v", v, err)
+	}
+}
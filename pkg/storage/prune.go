@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// ErrPruned is returned for blocks whose body was deleted by pruning; their header is still stored
+var ErrPruned = errors.New("storage: block body has been pruned")
+
+// pruneBatchSize is the number of blocks pruned per write, so an interrupted run
+// loses little work and resumes where it stopped
+const pruneBatchSize = 500
+
+// RetainedRange describes which blocks a node still holds
+type RetainedRange struct {
+	// HeadersFrom is the lowest height with a stored header. It's above zero only
+	// if the node was started from a snapshot.
+	HeadersFrom int64
+	// BodiesFrom is the lowest height with a stored body, i.e. full block
+	BodiesFrom int64
+	Latest     int64
+}
+
+// GetRetainedRange returns the heights this node can serve headers and full blocks for
+func (d *DB) GetRetainedRange() (*RetainedRange, error) {
+	latest, err := d.GetLatestBlock()
+	if err != nil {
+		return nil, err
+	}
+	base, err := d.BaseHeight()
+	if err != nil {
+		return nil, err
+	}
+	bodiesFrom, err := d.prunedHeight()
+	if err != nil {
+		return nil, err
+	}
+	if bodiesFrom < base {
+		bodiesFrom = base
+	}
+	return &RetainedRange{HeadersFrom: base, BodiesFrom: bodiesFrom, Latest: latest.Height}, nil
+}
+
+// prunedHeight returns the height below which block bodies have been pruned
+func (d *DB) prunedHeight() (int64, error) {
+	data, err := d.store.Get(prunedKey)
+	if err == ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+// Prune deletes the bodies of all but the last keep blocks, together with their
+// history index entries. Headers and the transaction index are kept forever.
+// Blocks are final once committed, there are no reorgs to roll back, so the node
+// never needs old state versions; the only ones it keeps are its snapshots, and
+// the snapshot blocks keep their bodies so the snapshots can still be served.
+// It returns the number of block bodies deleted.
+func (d *DB) Prune(keep int64) (int, error) {
+	if keep < 1 {
+		return 0, fmt.Errorf("must keep at least one block, got %d", keep)
+	}
+
+	rng, err := d.GetRetainedRange()
+	if err != nil {
+		return 0, err
+	}
+	target := rng.Latest - keep + 1
+	snapshots, err := d.ListSnapshots()
+	if err != nil {
+		return 0, err
+	}
+	if n := len(snapshots); n > 0 && snapshots[n-1].Height < target {
+		target = snapshots[n-1].Height
+	}
+
+	pruned := 0
+	for from := rng.BodiesFrom; from < target; from += pruneBatchSize {
+		to := from + pruneBatchSize
+		if to > target {
+			to = target
+		}
+
+		batch := new(Batch)
+		for h := from; h < to; h++ {
+			blk, err := d.GetBlockByHeight(h)
+			if err != nil {
+				return pruned, fmt.Errorf("block at height %d: %w", h, err)
+			}
+			if err := d.unindexHistory(batch, blk); err != nil {
+				return pruned, err
+			}
+			batch.Delete(bodyKey(blk.CurrentBlockHash))
+		}
+		batch.Put(prunedKey, []byte(strconv.FormatInt(to, 10)))
+		if err := d.store.Write(batch); err != nil {
+			return pruned, err
+		}
+		pruned += int(to - from)
+	}
+	return pruned, nil
+}
+
+// Compact asks the storage engine to reclaim the space of deleted data,
+// if it supports that. It's a no-op otherwise.
+func (d *DB) Compact() error {
+	if c, ok := d.store.(Compacter); ok {
+		return c.Compact()
+	}
+	return nil
+}
+
+// StartPruning runs Prune followed by Compact in the background every interval
+func (d *DB) StartPruning(keep int64, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			n, err := d.Prune(keep)
+			if err != nil {
+				log.Println("⚠️ Pruning failed:", err)
+				continue
+			}
+			if n == 0 {
+				continue
+			}
+			log.Printf("✂️ Pruned %d block bodies", n)
+			if err := d.Compact(); err != nil {
+				log.Println("⚠️ Compaction failed:", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,116 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"golang-chain/pkg/storage"
+)
+
+// addTransfer adds a block sending bob a tenth of its height. Version 1 transactions
+// have no nonce, so the amounts keep their hashes apart.
+func addTransfer(t *testing.T, c *testChain) {
+	t.Helper()
+	height := c.blocks[len(c.blocks)-1].Height + 1
+	c.add(t, c.tx(t, "bob", float64(height)/10))
+}
+
+// snapshotAt adds transfers up to height, then takes a snapshot there
+func snapshotAt(t *testing.T, c *testChain, height int64) {
+	t.Helper()
+	for c.blocks[len(c.blocks)-1].Height < height {
+		addTransfer(t, c)
+	}
+	if _, err := c.db.CreateSnapshot(); err != nil {
+		t.Fatalf("CreateSnapshot at %d: %v", height, err)
+	}
+}
+
+func checkRange(t *testing.T, db *storage.DB, want storage.RetainedRange) {
+	t.Helper()
+	rng, err := db.GetRetainedRange()
+	if err != nil {
+		t.Fatalf("GetRetainedRange: %v", err)
+	}
+	if *rng != want {
+		t.Fatalf("GetRetainedRange: got %+v, want %+v", *rng, want)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	c := newTestChain(t, 10)
+	snapshotAt(t, c, 2)
+	snapshotAt(t, c, 4)
+	addTransfer(t, c)
+	addTransfer(t, c)
+	checkRange(t, c.db, storage.RetainedRange{HeadersFrom: 0, BodiesFrom: 0, Latest: 6})
+
+	if _, err := c.db.Prune(0); err == nil {
+		t.Fatalf("Prune(0) accepted")
+	}
+
+	// Keeping 2 blocks would prune up to height 5, but the oldest snapshot at height 2
+	// must still be served with its block
+	if n, err := c.db.Prune(2); err != nil || n != 2 {
+		t.Fatalf("Prune clamped to the snapshot: got %d, %v", n, err)
+	}
+	checkRange(t, c.db, storage.RetainedRange{HeadersFrom: 0, BodiesFrom: 2, Latest: 6})
+
+	// Once that snapshot is replaced, pruning moves on to the next one
+	snapshotAt(t, c, 6)
+	if n, err := c.db.Prune(2); err != nil || n != 2 {
+		t.Fatalf("Prune after a new snapshot: got %d, %v", n, err)
+	}
+	checkRange(t, c.db, storage.RetainedRange{HeadersFrom: 0, BodiesFrom: 4, Latest: 6})
+	if n, err := c.db.Prune(2); err != nil || n != 0 {
+		t.Fatalf("Prune with nothing left to prune: got %d, %v", n, err)
+	}
+
+	for h := int64(0); h <= 6; h++ {
+		header, err := c.db.GetHeaderByHeight(h)
+		if err != nil || header.CurrentBlockHash != c.blocks[h].CurrentBlockHash {
+			t.Errorf("header at %d after pruning: got %+v, %v", h, header, err)
+		}
+		blk, err := c.db.GetBlockByHeight(h)
+		if h < 4 {
+			if !errors.Is(err, storage.ErrPruned) {
+				t.Errorf("pruned block at %d: want ErrPruned, got %v", h, err)
+			}
+			continue
+		}
+		if err != nil || blk.CurrentBlockHash != c.blocks[h].CurrentBlockHash {
+			t.Errorf("kept block at %d: got %v", h, err)
+		}
+	}
+
+	// History only lists the transactions of the blocks still held
+	locs, total, err := c.db.GetAccountHistory("bob", 0, 10)
+	if err != nil || total != 3 || len(locs) != 3 || locs[0].Height != 6 || locs[2].Height != 4 {
+		t.Errorf("history after pruning: got %v, %d, %v", locs, total, err)
+	}
+
+	// Balances are untouched
+	if bal, _ := c.db.GetBalance("bob"); bal.Text('f', 2) != "2.10" {
+		t.Errorf("balance of bob after pruning: %s", bal.Text('f', 2))
+	}
+}
+
+func TestRetainedRangeFromSnapshot(t *testing.T) {
+	src, manifest, block, chunks := snapshotSource(t)
+	db := freshDB(t)
+	if err := db.RestoreSnapshot(block, manifest, chunks); err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+	checkRange(t, db, storage.RetainedRange{HeadersFrom: 2, BodiesFrom: 2, Latest: 2})
+
+	// The restored snapshot is served too, so its block is never pruned
+	for i := 0; i < 3; i++ {
+		if err := db.SaveBlock(src.add(t, src.tx(t, "dave", float64(i+1)))); err != nil {
+			t.Fatalf("SaveBlock: %v", err)
+		}
+	}
+	if n, err := db.Prune(1); err != nil || n != 0 {
+		t.Fatalf("Prune above the snapshot: got %d, %v", n, err)
+	}
+	checkRange(t, db, storage.RetainedRange{HeadersFrom: 2, BodiesFrom: 2, Latest: 5})
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+
+	"golang-chain/pkg/state"
+)
+
+// reindexBatchSize is the number of blocks replayed per write
+const reindexBatchSize = 500
+
+// Reindexing reports whether a reindex was started and hasn't finished yet
+func (d *DB) Reindexing() (bool, error) {
+	return d.store.Has(reindexKey)
+}
+
+// Reindex throws away all data derived from the blocks (account state, transaction
+// and history indexes, state snapshots) and rebuilds it by replaying every stored block.
+//
+// The state height advances with every batch of replayed blocks, so an interrupted
+// reindex resumes where it stopped when Reindex is called again, instead of starting over.
+// progress is called after each batch with the last replayed height and the head height.
+func (d *DB) Reindex(progress func(height, latest int64)) error {
+	rng, err := d.GetRetainedRange()
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if rng.BodiesFrom > 0 {
+		return fmt.Errorf("full blocks are only kept from height %d, can't replay the chain", rng.BodiesFrom)
+	}
+
+	reindexing, err := d.Reindexing()
+	if err != nil {
+		return err
+	}
+	if !reindexing {
+		batch := new(Batch)
+		for _, prefix := range [][]byte{statePrefix, historyPrefix, txPrefix, snapshotManifestPrefix, snapshotChunkPrefix} {
+			if err := d.deletePrefix(batch, prefix); err != nil {
+				return err
+			}
+		}
+		batch.Put(stateHeightKey, []byte("-1"))
+		batch.Put(reindexKey, []byte("1"))
+		if err := d.store.Write(batch); err != nil {
+			return err
+		}
+	}
+
+	stateHeight, err := d.getStateHeight()
+	if err != nil {
+		return err
+	}
+	for from := stateHeight + 1; from <= rng.Latest; from += reindexBatchSize {
+		to := from + reindexBatchSize - 1
+		if to > rng.Latest {
+			to = rng.Latest
+		}
+
+		// Accounts changed by earlier blocks of the batch stay in st until it's written
+		batch := new(Batch)
+		st := state.New(d)
+		st.UseTxIndex(d)
+		for h := from; h <= to; h++ {
+			blk, err := d.GetBlockByHeight(h)
+			if err != nil {
+				return fmt.Errorf("block at height %d: %w", h, err)
+			}
+			if err := st.ApplyBlock(blk); err != nil {
+				return fmt.Errorf("block at height %d: %w", h, err)
+			}
+			if err := d.indexTransactions(batch, blk); err != nil {
+				return err
+			}
+		}
+		if err := putAccounts(batch, st); err != nil {
+			return err
+		}
+		batch.Put(stateHeightKey, []byte(strconv.FormatInt(to, 10)))
+		if err := d.store.Write(batch); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(to, rng.Latest)
+		}
+	}
+
+	return d.store.Delete(reindexKey)
+}
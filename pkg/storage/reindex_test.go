@@ -0,0 +1,99 @@
+package storage_test
+
+import (
+	"strings"
+	"testing"
+
+	"golang-chain/pkg/storage"
+)
+
+// errInterrupted stops a reindex from its progress callback, like a crash would
+type errInterrupted struct{}
+
+// reindexUntil runs Reindex and interrupts it after the batch ending at height
+func reindexUntil(t *testing.T, db *storage.DB, height int64) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != (errInterrupted{}) {
+			t.Fatalf("reindex wasn't interrupted: %v", r)
+		}
+	}()
+	err := db.Reindex(func(h, _ int64) {
+		if h == height {
+			panic(errInterrupted{})
+		}
+	})
+	t.Fatalf("Reindex finished without reaching height %d: %v", height, err)
+}
+
+func TestReindexResumes(t *testing.T) {
+	c := newTestChain(t, 10)
+	// More than two batches of blocks. Blocks without transactions, as the replay starts
+	// from an empty state and the chain doesn't issue coins.
+	for i := 0; i < 1100; i++ {
+		c.add(t)
+	}
+	if _, err := c.db.CreateSnapshot(); err != nil {
+		t.Fatalf("CreateSnapshot: %v", err)
+	}
+
+	reindexUntil(t, c.db, 499)
+	if reindexing, err := c.db.Reindexing(); err != nil || !reindexing {
+		t.Fatalf("Reindexing after the interruption: got %v, %v", reindexing, err)
+	}
+	if got := dump(t, c.store, "m/state_height"); got["m/state_height"] != "499" {
+		t.Fatalf("state height after the interruption: %v", got)
+	}
+	if list, err := c.db.ListSnapshots(); err != nil || len(list) != 0 {
+		t.Fatalf("snapshots kept by the reindex: %v, %v", list, err)
+	}
+	// The rest of the startup checks leave the unfinished reindex alone
+	if err := c.db.CheckConsistency(); err != nil {
+		t.Fatalf("CheckConsistency during the reindex: %v", err)
+	}
+
+	var heights []int64
+	err := c.db.Reindex(func(h, latest int64) {
+		if latest != 1100 {
+			t.Errorf("progress reported head %d", latest)
+		}
+		heights = append(heights, h)
+	})
+	if err != nil {
+		t.Fatalf("resumed Reindex: %v", err)
+	}
+	if len(heights) != 2 || heights[0] != 999 || heights[1] != 1100 {
+		t.Fatalf("resumed Reindex started over: progress %v", heights)
+	}
+	if reindexing, err := c.db.Reindexing(); err != nil || reindexing {
+		t.Fatalf("Reindexing after finishing: got %v, %v", reindexing, err)
+	}
+	if got := dump(t, c.store, "m/state_height"); got["m/state_height"] != "1100" {
+		t.Fatalf("state height after the reindex: %v", got)
+	}
+
+	if err := c.db.CheckConsistency(); err != nil {
+		t.Errorf("CheckConsistency after the reindex: %v", err)
+	}
+}
+
+func TestReindexRefusesPrunedChain(t *testing.T) {
+	c := newTestChain(t, 10)
+	c.add(t, c.tx(t, "bob", 1))
+	c.add(t)
+	if _, err := c.db.Prune(1); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	before := dump(t, c.store, "")
+
+	err := c.db.Reindex(nil)
+	if err == nil || !strings.Contains(err.Error(), "only kept from height 2") {
+		t.Fatalf("Reindex of a pruned chain: got %v", err)
+	}
+	if !equalMaps(before, dump(t, c.store, "")) {
+		t.Errorf("refused Reindex changed the store")
+	}
+	if bal, _ := c.db.GetBalance("bob"); bal.Text('f', 2) != "1.00" {
+		t.Errorf("balance of bob after the refused reindex: %s", bal.Text('f', 2))
+	}
+}
@@ -0,0 +1,292 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/state"
+)
+
+// A state snapshot holds every account as of the block at some height. The accounts are
+// sorted by address and split into chunks, and the manifest lists the hash of each chunk
+// so a downloaded chunk can be checked on arrival. The assembled accounts must hash to
+// the state root of the snapshot block, which is what makes a snapshot trustworthy.
+const (
+	snapshotChunkSize = 1000 // accounts per chunk
+	snapshotsKept     = 2
+)
+
+// SnapshotManifest describes a stored state snapshot
+type SnapshotManifest struct {
+	Height    int64
+	BlockHash string
+	StateRoot string
+	// Chunks holds the hex-encoded SHA-256 of every chunk, in order
+	Chunks []string
+}
+
+// snapshotEntry is one account inside a chunk
+type snapshotEntry struct {
+	Address string          `json:"address"`
+	Account json.RawMessage `json:"account"`
+}
+
+// SetSnapshotInterval makes SaveBlock take a state snapshot every given number of blocks.
+// Zero, the default, disables snapshots.
+func (d *DB) SetSnapshotInterval(blocks int64) {
+	d.snapshotInterval = blocks
+}
+
+// maybeSnapshot takes a snapshot after the block was saved if it falls on the interval.
+// A failed snapshot doesn't affect the chain, so it's only logged.
+func (d *DB) maybeSnapshot(block *blockchain.Block) {
+	if d.snapshotInterval <= 0 || block.Height == 0 || block.Height%d.snapshotInterval != 0 {
+		return
+	}
+	if _, err := d.CreateSnapshot(); err != nil {
+		log.Printf("⚠️ Failed to create state snapshot at height %d: %v", block.Height, err)
+	}
+}
+
+// CreateSnapshot stores a snapshot of the state at the latest block
+// and deletes all but the newest snapshots.
+func (d *DB) CreateSnapshot() (*SnapshotManifest, error) {
+	latest, err := d.GetLatestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if latest.StateRoot == "" {
+		return nil, fmt.Errorf("block at height %d has no state root", latest.Height)
+	}
+
+	manifest := &SnapshotManifest{
+		Height:    latest.Height,
+		BlockHash: latest.CurrentBlockHash,
+		StateRoot: latest.StateRoot,
+	}
+	batch := new(Batch)
+	st := state.New(nil)
+	var entries []snapshotEntry
+
+	flush := func() error {
+		data, err := json.Marshal(entries)
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256(data)
+		batch.Put(snapshotChunkKey(manifest.Height, len(manifest.Chunks)), data)
+		manifest.Chunks = append(manifest.Chunks, hex.EncodeToString(hash[:]))
+		entries = nil
+		return nil
+	}
+
+	var iterErr error
+	err = d.store.Iterate(statePrefix, false, func(key, value []byte) bool {
+		acc, err := state.DecodeAccount(value)
+		if err != nil {
+			iterErr = err
+			return false
+		}
+		address := string(key[len(statePrefix):])
+		st.Set(address, acc)
+		entries = append(entries, snapshotEntry{Address: address, Account: append([]byte{}, value...)})
+		if len(entries) == snapshotChunkSize {
+			if iterErr = flush(); iterErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err == nil {
+		err = iterErr
+	}
+	if err == nil && len(entries) > 0 {
+		err = flush()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The state may have moved on if a block was saved meanwhile
+	if root, err := st.Root(); err != nil || root != manifest.StateRoot {
+		return nil, fmt.Errorf("state doesn't match the state root of block %d", manifest.Height)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	batch.Put(snapshotManifestKey(manifest.Height), data)
+	if err := d.store.Write(batch); err != nil {
+		return nil, err
+	}
+	log.Printf("📸 State snapshot taken at height %d (%d chunks)", manifest.Height, len(manifest.Chunks))
+
+	return manifest, d.pruneSnapshots()
+}
+
+// pruneSnapshots deletes all snapshots except the newest snapshotsKept
+func (d *DB) pruneSnapshots() error {
+	manifests, err := d.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	if len(manifests) <= snapshotsKept {
+		return nil
+	}
+
+	batch := new(Batch)
+	for _, m := range manifests[snapshotsKept:] {
+		batch.Delete(snapshotManifestKey(m.Height))
+		if err := d.deletePrefix(batch, snapshotChunksPrefix(m.Height)); err != nil {
+			return err
+		}
+	}
+	return d.store.Write(batch)
+}
+
+// ListSnapshots returns the manifests of all stored snapshots, newest first
+func (d *DB) ListSnapshots() ([]*SnapshotManifest, error) {
+	var manifests []*SnapshotManifest
+	var decodeErr error
+	err := d.store.Iterate(snapshotManifestPrefix, true, func(_, value []byte) bool {
+		var m SnapshotManifest
+		if decodeErr = json.Unmarshal(value, &m); decodeErr != nil {
+			return false
+		}
+		manifests = append(manifests, &m)
+		return true
+	})
+	if err == nil {
+		err = decodeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return manifests, nil
+}
+
+// GetSnapshotChunk returns the raw data of one chunk of the snapshot at height
+func (d *DB) GetSnapshotChunk(height int64, index int) ([]byte, error) {
+	return d.store.Get(snapshotChunkKey(height, index))
+}
+
+// VerifySnapshotChunk checks a downloaded chunk against the hash listed in the manifest
+func VerifySnapshotChunk(manifest *SnapshotManifest, index int, data []byte) error {
+	if index < 0 || index >= len(manifest.Chunks) {
+		return fmt.Errorf("chunk %d out of range", index)
+	}
+	hash := sha256.Sum256(data)
+	if hex.EncodeToString(hash[:]) != manifest.Chunks[index] {
+		return fmt.Errorf("chunk %d doesn't match its hash in the manifest", index)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the chain with the snapshot block and the state from the snapshot chunks,
+// so that syncing can continue right after the snapshot height. It's meant for a new node:
+// the database must hold nothing beyond the genesis block.
+//
+// The chunks are checked against the manifest, and the accounts they add up to against
+// the state root of the block, before anything is written. Blocks below the snapshot
+// are not available afterwards, and neither is their transaction history.
+func (d *DB) RestoreSnapshot(block *blockchain.Block, manifest *SnapshotManifest, chunks [][]byte) error {
+	if latest, err := d.GetLatestBlock(); err == nil && latest.Height > 0 {
+		return fmt.Errorf("database already holds blocks up to height %d", latest.Height)
+	} else if err != nil && err != ErrNotFound {
+		return err
+	}
+
+	if block.Height != manifest.Height || block.CurrentBlockHash != manifest.BlockHash {
+		return fmt.Errorf("block %d (%s) is not the snapshot block", block.Height, block.CurrentBlockHash)
+	}
+	if blockchain.HashBlock(block) != block.CurrentBlockHash {
+		return fmt.Errorf("snapshot block doesn't match its hash")
+	}
+	if block.StateRoot == "" || block.StateRoot != manifest.StateRoot {
+		return fmt.Errorf("snapshot state root doesn't match the block")
+	}
+	if len(chunks) != len(manifest.Chunks) {
+		return fmt.Errorf("got %d chunks, manifest lists %d", len(chunks), len(manifest.Chunks))
+	}
+
+	st := state.New(nil)
+	var prev []byte
+	for i, data := range chunks {
+		if err := VerifySnapshotChunk(manifest, i, data); err != nil {
+			return err
+		}
+		var entries []snapshotEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("chunk %d: %w", i, err)
+		}
+		for _, e := range entries {
+			// Strictly increasing addresses rule out an account listed twice
+			if prev != nil && bytes.Compare([]byte(e.Address), prev) <= 0 {
+				return fmt.Errorf("chunk %d: accounts out of order at %q", i, e.Address)
+			}
+			prev = []byte(e.Address)
+
+			acc, err := state.DecodeAccount(e.Account)
+			if err != nil {
+				return fmt.Errorf("chunk %d: account %q: %w", i, e.Address, err)
+			}
+			st.Set(e.Address, acc)
+		}
+	}
+	if root, err := st.Root(); err != nil || root != manifest.StateRoot {
+		return fmt.Errorf("snapshot accounts don't match state root %s", manifest.StateRoot)
+	}
+
+	// Replace everything the genesis block left behind
+	batch := new(Batch)
+	for _, prefix := range [][]byte{headerPrefix, bodyPrefix, heightPrefix, statePrefix, historyPrefix, txPrefix} {
+		if err := d.deletePrefix(batch, prefix); err != nil {
+			return err
+		}
+	}
+	if err := putBlock(batch, block); err != nil {
+		return err
+	}
+	if err := d.indexTransactions(batch, block); err != nil {
+		return err
+	}
+	if err := putAccounts(batch, st); err != nil {
+		return err
+	}
+
+	// Keep the snapshot so this node can serve it as well
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	batch.Put(snapshotManifestKey(manifest.Height), data)
+	for i, chunk := range chunks {
+		batch.Put(snapshotChunkKey(manifest.Height, i), chunk)
+	}
+
+	height := []byte(strconv.FormatInt(block.Height, 10))
+	batch.Put(baseKey, height)
+	batch.Put(stateHeightKey, height)
+	batch.Put(latestKey, []byte(block.CurrentBlockHash))
+	return d.store.Write(batch)
+}
+
+// BaseHeight returns the lowest height the database holds blocks for.
+// It's zero unless the node was started from a snapshot.
+func (d *DB) BaseHeight() (int64, error) {
+	data, err := d.store.Get(baseKey)
+	if err == ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
@@ -0,0 +1,183 @@
+package storage_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"golang-chain/pkg/blockchain"
+	"golang-chain/pkg/state"
+	"golang-chain/pkg/storage"
+)
+
+// snapshotSource returns a chain of a few blocks with a snapshot taken at its head,
+// together with the snapshot block and chunks a peer would serve
+func snapshotSource(t *testing.T) (*testChain, *storage.SnapshotManifest, *blockchain.Block, [][]byte) {
+	t.Helper()
+	c := newTestChain(t, 10)
+	c.add(t, c.tx(t, "bob", 1))
+	block := c.add(t, c.tx(t, "carol", 2), c.tx(t, "bob", 0.5))
+
+	manifest, err := c.db.CreateSnapshot()
+	if err != nil {
+		t.Fatalf("CreateSnapshot: %v", err)
+	}
+	var chunks [][]byte
+	for i := range manifest.Chunks {
+		chunk, err := c.db.GetSnapshotChunk(manifest.Height, i)
+		if err != nil {
+			t.Fatalf("GetSnapshotChunk(%d): %v", i, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return c, manifest, block, chunks
+}
+
+// freshDB returns a DB holding only the genesis block, like a new node
+func freshDB(t *testing.T) *storage.DB {
+	t.Helper()
+	db := storage.NewDBWithStore(storage.NewMemoryStore())
+	if err := db.SaveBlock(blockchain.CreateGenesisBlock()); err != nil {
+		t.Fatalf("SaveBlock(genesis): %v", err)
+	}
+	return db
+}
+
+// forgeChunk returns chunk with the balance of address replaced
+func forgeChunk(t *testing.T, chunk []byte, address string, balance float64) []byte {
+	t.Helper()
+	var entries []struct {
+		Address string          `json:"address"`
+		Account json.RawMessage `json:"account"`
+	}
+	if err := json.Unmarshal(chunk, &entries); err != nil {
+		t.Fatalf("Unmarshal chunk: %v", err)
+	}
+	found := false
+	for i := range entries {
+		if entries[i].Address != address {
+			continue
+		}
+		acc := state.NewAccount()
+		acc.Balance.SetFloat64(balance)
+		data, err := acc.Encode()
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		entries[i].Account = data
+		found = true
+	}
+	if !found {
+		t.Fatalf("chunk doesn't hold %s", address)
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatalf("Marshal chunk: %v", err)
+	}
+	return data
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	src, manifest, block, chunks := snapshotSource(t)
+	if manifest.Height != block.Height || manifest.StateRoot != block.StateRoot {
+		t.Fatalf("manifest %+v doesn't describe block %d", manifest, block.Height)
+	}
+
+	db := freshDB(t)
+	if err := db.RestoreSnapshot(block, manifest, chunks); err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+
+	latest, err := db.GetLatestBlock()
+	if err != nil || latest.CurrentBlockHash != block.CurrentBlockHash {
+		t.Fatalf("head after restoring: got %+v, %v", latest, err)
+	}
+	if base, err := db.BaseHeight(); err != nil || base != block.Height {
+		t.Fatalf("BaseHeight: got %d, %v", base, err)
+	}
+	st, err := db.LoadState()
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if root, err := st.Root(); err != nil || root != block.StateRoot {
+		t.Fatalf("restored state root %s, %v, want %s", root, err, block.StateRoot)
+	}
+	for _, addr := range []string{src.sender.Public().Address(), "bob", "carol"} {
+		want, _ := src.db.GetBalance(addr)
+		if got, _ := db.GetBalance(addr); got.Cmp(want) != 0 {
+			t.Errorf("balance of %s: got %s, want %s", addr, got.Text('f', 8), want.Text('f', 8))
+		}
+	}
+	if list, err := db.ListSnapshots(); err != nil || len(list) != 1 || list[0].Height != manifest.Height {
+		t.Errorf("restored node doesn't serve the snapshot: %v, %v", list, err)
+	}
+	if err := db.CheckConsistency(); err != nil {
+		t.Errorf("CheckConsistency: %v", err)
+	}
+
+	// Syncing continues with the block after the snapshot
+	next := src.add(t, src.tx(t, "dave", 1))
+	if err := db.SaveBlock(next); err != nil {
+		t.Fatalf("SaveBlock after the snapshot: %v", err)
+	}
+	if got, _ := db.GetBalance("dave"); got.Text('f', 8) != "1.00000000" {
+		t.Errorf("balance of dave after the next block: %s", got.Text('f', 8))
+	}
+
+	// Only a new node can be restored
+	if err := db.RestoreSnapshot(block, manifest, chunks); err == nil || !strings.Contains(err.Error(), "already holds blocks") {
+		t.Errorf("RestoreSnapshot over a chain: got %v", err)
+	}
+}
+
+func TestRestoreSnapshotRejects(t *testing.T) {
+	_, manifest, block, chunks := snapshotSource(t)
+
+	// A chunk crediting bob, and a manifest that lists its hash
+	forged := forgeChunk(t, chunks[0], "bob", 9)
+	sum := sha256.Sum256(forged)
+	forgedManifest := *manifest
+	forgedManifest.Chunks = []string{hex.EncodeToString(sum[:])}
+
+	otherRoot := *manifest
+	otherRoot.StateRoot = strings.Repeat("0", 64)
+
+	tests := []struct {
+		name     string
+		manifest *storage.SnapshotManifest
+		chunks   [][]byte
+		want     string
+	}{
+		{"chunk that doesn't match its hash", manifest, [][]byte{forged}, "doesn't match its hash"},
+		{"accounts that don't add up to the state root", &forgedManifest, [][]byte{forged}, "don't match state root"},
+		{"manifest with another state root", &otherRoot, chunks, "state root doesn't match the block"},
+		{"missing chunk", manifest, nil, "got 0 chunks"},
+	}
+	for _, tc := range tests {
+		db := freshDB(t)
+		err := db.RestoreSnapshot(block, tc.manifest, tc.chunks)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want an error containing %q, got %v", tc.name, tc.want, err)
+			continue
+		}
+		// Nothing was written
+		if latest, err := db.GetLatestBlock(); err != nil || latest.Height != 0 {
+			t.Errorf("%s: head moved: %+v, %v", tc.name, latest, err)
+		}
+		if bal, _ := db.GetBalance("bob"); bal.Sign() != 0 {
+			t.Errorf("%s: state was written", tc.name)
+		}
+	}
+
+	if err := storage.VerifySnapshotChunk(manifest, 0, chunks[0]); err != nil {
+		t.Errorf("VerifySnapshotChunk: %v", err)
+	}
+	if err := storage.VerifySnapshotChunk(manifest, 0, forged); err == nil {
+		t.Errorf("VerifySnapshotChunk accepted a tampered chunk")
+	}
+	if err := storage.VerifySnapshotChunk(manifest, len(manifest.Chunks), chunks[0]); err == nil {
+		t.Errorf("VerifySnapshotChunk accepted an index past the last chunk")
+	}
+}
@@ -0,0 +1,198 @@
+// Package storagetest provides the conformance suite every storage.Store implementation must pass.
+//
+// A backend's test calls Run with a function that opens a fresh, empty store:
+//
+//	func TestMemoryStore(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) storage.Store {
+//			return storage.NewMemoryStore()
+//		})
+//	}
+package storagetest
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"golang-chain/pkg/storage"
+)
+
+// Run executes the whole suite. open is called once per subtest and must return an empty store;
+// the suite closes it when the subtest ends.
+func Run(t *testing.T, open func(t *testing.T) storage.Store) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Store)
+	}{
+		{"GetPutDelete", testGetPutDelete},
+		{"ValuesAreCopied", testValuesAreCopied},
+		{"BatchWrite", testBatchWrite},
+		{"IteratePrefix", testIteratePrefix},
+		{"IterateReverse", testIterateReverse},
+		{"IterateStopsEarly", testIterateStopsEarly},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := open(t)
+			defer s.Close()
+			tc.fn(t, s)
+		})
+	}
+}
+
+func testGetPutDelete(t *testing.T, s storage.Store) {
+	key := []byte("k")
+
+	if _, err := s.Get(key); err != storage.ErrNotFound {
+		t.Fatalf("Get on missing key: want ErrNotFound, got %v", err)
+	}
+	if ok, err := s.Has(key); err != nil || ok {
+		t.Fatalf("Has on missing key: got %v, %v", ok, err)
+	}
+
+	mustPut(t, s, "k", "v1")
+	mustPut(t, s, "k", "v2")
+	mustGet(t, s, "k", "v2")
+	if ok, err := s.Has(key); err != nil || !ok {
+		t.Fatalf("Has on present key: got %v, %v", ok, err)
+	}
+
+	if err := s.Delete(key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(key); err != storage.ErrNotFound {
+		t.Fatalf("Get after Delete: want ErrNotFound, got %v", err)
+	}
+	if err := s.Delete(key); err != nil {
+		t.Fatalf("Delete of missing key: %v", err)
+	}
+}
+
+func testValuesAreCopied(t *testing.T, s storage.Store) {
+	value := []byte("original")
+	if err := s.Put([]byte("k"), value); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	copy(value, "mutated!")
+	mustGet(t, s, "k", "original")
+
+	got, _ := s.Get([]byte("k"))
+	copy(got, "mutated!")
+	mustGet(t, s, "k", "original")
+}
+
+func testBatchWrite(t *testing.T, s storage.Store) {
+	mustPut(t, s, "a", "old")
+	mustPut(t, s, "b", "gone")
+
+	batch := new(storage.Batch)
+	batch.Put([]byte("a"), []byte("new"))
+	batch.Delete([]byte("b"))
+	batch.Put([]byte("c"), []byte("1"))
+	batch.Put([]byte("c"), []byte("2"))
+
+	// Nothing is visible until the batch is written
+	mustGet(t, s, "a", "old")
+	if _, err := s.Get([]byte("c")); err != storage.ErrNotFound {
+		t.Fatalf("batch applied before Write: %v", err)
+	}
+
+	if err := s.Write(batch); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	mustGet(t, s, "a", "new")
+	mustGet(t, s, "c", "2")
+	if _, err := s.Get([]byte("b")); err != storage.ErrNotFound {
+		t.Fatalf("deleted key still present: %v", err)
+	}
+}
+
+func testIteratePrefix(t *testing.T, s storage.Store) {
+	for _, k := range []string{"p/b", "p/a", "p/c", "pa", "o/z", "q/a"} {
+		mustPut(t, s, k, "v-"+k)
+	}
+
+	got := collect(t, s, "p/", false)
+	want := []string{"p/a", "p/b", "p/c"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("Iterate(p/): want %v, got %v", want, got)
+	}
+
+	all := collect(t, s, "", false)
+	if len(all) != 6 || all[0] != "o/z" || all[5] != "q/a" {
+		t.Fatalf("Iterate(nil): unexpected order %v", all)
+	}
+
+	var value []byte
+	err := s.Iterate([]byte("p/b"), false, func(_, v []byte) bool {
+		value = append([]byte{}, v...)
+		return true
+	})
+	if err != nil || string(value) != "v-p/b" {
+		t.Fatalf("Iterate value: got %q, %v", value, err)
+	}
+}
+
+func testIterateReverse(t *testing.T, s storage.Store) {
+	for _, k := range []string{"p/a", "p/b", "p/c", "p\xff", "q"} {
+		mustPut(t, s, k, "v")
+	}
+
+	got := collect(t, s, "p/", true)
+	want := []string{"p/c", "p/b", "p/a"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("reverse Iterate(p/): want %v, got %v", want, got)
+	}
+
+	got = collect(t, s, "", true)
+	if len(got) != 5 || got[0] != "q" || got[4] != "p/a" {
+		t.Fatalf("reverse Iterate(nil): unexpected order %q", got)
+	}
+
+	if got := collect(t, s, "z", true); len(got) != 0 {
+		t.Fatalf("reverse Iterate on empty range: got %v", got)
+	}
+}
+
+func testIterateStopsEarly(t *testing.T, s storage.Store) {
+	for i := 0; i < 5; i++ {
+		mustPut(t, s, fmt.Sprintf("k%d", i), "v")
+	}
+	calls := 0
+	err := s.Iterate([]byte("k"), false, func(_, _ []byte) bool {
+		calls++
+		return calls < 2
+	})
+	if err != nil || calls != 2 {
+		t.Fatalf("Iterate didn't stop: %d calls, %v", calls, err)
+	}
+}
+
+func mustPut(t *testing.T, s storage.Store, key, value string) {
+	t.Helper()
+	if err := s.Put([]byte(key), []byte(value)); err != nil {
+		t.Fatalf("Put(%q): %v", key, err)
+	}
+}
+
+func mustGet(t *testing.T, s storage.Store, key, want string) {
+	t.Helper()
+	got, err := s.Get([]byte(key))
+	if err != nil || !bytes.Equal(got, []byte(want)) {
+		t.Fatalf("Get(%q): want %q, got %q, %v", key, want, got, err)
+	}
+}
+
+func collect(t *testing.T, s storage.Store, prefix string, reverse bool) []string {
+	t.Helper()
+	var keys []string
+	err := s.Iterate([]byte(prefix), reverse, func(k, _ []byte) bool {
+		keys = append(keys, string(k))
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Iterate(%q): %v", prefix, err)
+	}
+	return keys
+}
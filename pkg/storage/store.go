@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned by Store.Get when the key doesn't exist
+var ErrNotFound = errors.New("storage: not found")
+
+// Store is the key-value engine underneath DB.
+// Keys are ordered bytewise; implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored under key, or ErrNotFound
+	Get(key []byte) ([]byte, error)
+	Has(key []byte) (bool, error)
+	Put(key, value []byte) error
+	Delete(key []byte) error
+
+	// Write applies all operations of the batch atomically and durably
+	Write(batch *Batch) error
+
+	// Iterate calls fn for every key starting with prefix, in ascending order
+	// (or descending if reverse is set), until fn returns false.
+	// Key and value are only valid during the call.
+	Iterate(prefix []byte, reverse bool, fn func(key, value []byte) bool) error
+
+	Close() error
+}
+
+// Compacter is implemented by stores that can reclaim the space left by deleted keys
+type Compacter interface {
+	Compact() error
+}
+
+// Batch collects writes that are applied together by Store.Write
+type Batch struct {
+	ops []batchOp
+}
+
+type batchOp struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// Put queues storing value under key. Both slices are copied.
+func (b *Batch) Put(key, value []byte) {
+	b.ops = append(b.ops, batchOp{
+		key:   append([]byte{}, key...),
+		value: append([]byte{}, value...),
+	})
+}
+
+// Delete queues removing key. The slice is copied.
+func (b *Batch) Delete(key []byte) {
+	b.ops = append(b.ops, batchOp{key: append([]byte{}, key...), delete: true})
+}
+
+// Len returns the number of queued operations
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// Supported storage engines
+const (
+	EngineLevelDB = "leveldb"
+	EngineBolt    = "bbolt"
+	EngineMemory  = "memory"
+)
+
+// OpenStore opens the key-value store of the given engine at path.
+// An empty engine selects LevelDB; the memory engine ignores path.
+func OpenStore(engine, path string) (Store, error) {
+	switch engine {
+	case "", EngineLevelDB:
+		return OpenLevelDBStore(path)
+	case EngineBolt:
+		return OpenBoltStore(path)
+	case EngineMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage engine %q", engine)
+	}
+}
+
+// OpenStoreReadOnly opens an existing store of the given engine at path without allowing writes
+func OpenStoreReadOnly(engine, path string) (Store, error) {
+	switch engine {
+	case "", EngineLevelDB:
+		return OpenLevelDBStoreReadOnly(path)
+	case EngineBolt:
+		return OpenBoltStoreReadOnly(path)
+	default:
+		return nil, fmt.Errorf("storage engine %q can't be opened read-only", engine)
+	}
+}
+
+// prefixEnd returns the smallest key greater than every key starting with prefix,
+// or nil if there is none (empty prefix or all 0xff bytes).
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
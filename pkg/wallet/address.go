@@ -0,0 +1,233 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Accounts are keyed by the hex SHA-256 address returned by PublicKeyToAddress, in the
+// state and in transactions. Users see and type them in a checksummed form instead:
+// Bech32m (BIP-350) with a human-readable part naming the network, an address
+// version and the 32-byte account hash, e.g. "gc1q..." on mainnet. The checksum
+// detects any 4 mistyped characters, and an address of another network is rejected.
+
+// NetworkEnv is the environment variable naming the network addresses are encoded for
+const NetworkEnv = "NETWORK"
+
+// DefaultNetwork is used when NETWORK isn't set
+const DefaultNetwork = "mainnet"
+
+// AddressVersion is the version of the encoded address, the first 5-bit group of its data
+const AddressVersion = 0
+
+// NetworkHRPs maps each network to the human-readable part of its addresses
+var NetworkHRPs = map[string]string{
+	"mainnet": "gc",
+	"testnet": "tgc",
+	"devnet":  "dgc",
+}
+
+// ErrInvalidAddress is returned for strings that aren't a valid encoded address
+var ErrInvalidAddress = errors.New("invalid address")
+
+const (
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32mConst  = 0x2bc830a3
+	bech32MaxLen  = 90
+	addressLen    = 32
+)
+
+// network is the network set by SetNetwork, overriding NETWORK
+var network string
+
+// SetNetwork makes the package encode addresses for the named network, e.g. from a
+// command line flag or configuration file
+func SetNetwork(name string) {
+	network = name
+	invalidateAddressIndex()
+}
+
+// AddressHRP returns the human-readable part of addresses on the configured network
+func AddressHRP() (string, error) {
+	name := network
+	if name == "" {
+		name = os.Getenv(NetworkEnv)
+	}
+	if name == "" {
+		name = DefaultNetwork
+	}
+	hrp, ok := NetworkHRPs[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("unknown network %q (want mainnet, testnet or devnet)", name)
+	}
+	return hrp, nil
+}
+
+// FormatAddress encodes a hex account address as returned by PublicKeyToAddress
+func FormatAddress(address string) (string, error) {
+	hash, err := hex.DecodeString(address)
+	if err != nil || len(hash) != addressLen {
+		return "", fmt.Errorf("%w: %q isn't a %d-byte hex account address", ErrInvalidAddress, address, addressLen)
+	}
+	hrp, err := AddressHRP()
+	if err != nil {
+		return "", err
+	}
+	data := append([]byte{AddressVersion}, convertBits(hash, 8, 5, true)...)
+	return bech32mEncode(hrp, data), nil
+}
+
+// ParseAddress decodes an encoded address, checking its checksum, network and version,
+// and returns the hex account address used in the state and in transactions
+func ParseAddress(s string) (string, error) {
+	if raw, err := hex.DecodeString(s); err == nil && len(raw) == addressLen {
+		return "", fmt.Errorf("%w %q: raw hex addresses have no checksum, use the encoded form", ErrInvalidAddress, s)
+	}
+	hrp, data, err := bech32mDecode(s)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalidAddress, s, err)
+	}
+	want, err := AddressHRP()
+	if err != nil {
+		return "", err
+	}
+	if hrp != want {
+		for network, h := range NetworkHRPs {
+			if h == hrp {
+				return "", fmt.Errorf("%w %q: it is a %s address", ErrInvalidAddress, s, network)
+			}
+		}
+		return "", fmt.Errorf("%w %q: unknown prefix %q", ErrInvalidAddress, s, hrp)
+	}
+	if len(data) == 0 || data[0] != AddressVersion {
+		return "", fmt.Errorf("%w %q: unsupported address version", ErrInvalidAddress, s)
+	}
+	hash, ok := convertBitsStrict(data[1:])
+	if !ok || len(hash) != addressLen {
+		return "", fmt.Errorf("%w %q: wrong length", ErrInvalidAddress, s)
+	}
+	return hex.EncodeToString(hash), nil
+}
+
+// DisplayAddress returns the encoded form of a hex account address for printing,
+// or the address unchanged if it can't be encoded
+func DisplayAddress(address string) string {
+	if s, err := FormatAddress(address); err == nil {
+		return s
+	}
+	return address
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32mEncode encodes 5-bit data groups under hrp with a Bech32m checksum
+func bech32mEncode(hrp string, data []byte) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	mod := bech32Polymod(values) ^ bech32mConst
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(mod>>(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// bech32mDecode splits s into its hrp and 5-bit data groups and checks its Bech32m checksum
+func bech32mDecode(s string) (string, []byte, error) {
+	if len(s) > bech32MaxLen {
+		return "", nil, errors.New("too long")
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, errors.New("mixed case")
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, errors.New("missing prefix or checksum")
+	}
+	hrp := s[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, errors.New("invalid character in prefix")
+		}
+	}
+	data := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		d := strings.IndexByte(bech32Charset, s[i])
+		if d < 0 {
+			return "", nil, fmt.Errorf("invalid character %q", s[i])
+		}
+		data = append(data, byte(d))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != bech32mConst {
+		return "", nil, errors.New("checksum mismatch, check for typos")
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+// convertBits regroups data from fromBits-bit to toBits-bit groups, padding the last group with zeros
+func convertBits(data []byte, fromBits, toBits uint, pad bool) []byte {
+	var acc uint32
+	var bits uint
+	maxv := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, v := range data {
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad && bits > 0 {
+		out = append(out, byte(acc<<(toBits-bits)&maxv))
+	}
+	return out
+}
+
+// convertBitsStrict turns 5-bit groups back into bytes, rejecting more than 4 bits of
+// padding or padding that isn't zero, so every address has a single encoding
+func convertBitsStrict(data []byte) ([]byte, bool) {
+	if len(data)*5%8 >= 5 {
+		return nil, false
+	}
+	out := convertBits(data, 5, 8, false)
+	if rem := len(data) * 5 % 8; rem > 0 && data[len(data)-1]&(1<<rem-1) != 0 {
+		return nil, false
+	}
+	return out, true
+}
@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Test vectors of BIP-350
+func TestBech32mVectors(t *testing.T) {
+	valid := []string{
+		"A1LQFN3A",
+		"a1lqfn3a",
+		"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6",
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+		"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8",
+		"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+		"?1v759aa",
+	}
+	for _, s := range valid {
+		hrp, data, err := bech32mDecode(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		if got := bech32mEncode(hrp, data); got != strings.ToLower(s) {
+			t.Errorf("%q: encodes back to %q", s, got)
+		}
+	}
+
+	invalid := map[string]string{
+		"\x201xj0phk": "prefix character out of range",
+		"\x7f1g6xzxy": "prefix character out of range",
+		"\x801vctc34": "prefix character out of range",
+		"an84characterslonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11d6pts4": "too long",
+		"qyrz8wqd2c9m":  "no separator",
+		"1qyrz8wqd2c9m": "empty prefix",
+		"y1b0jsk6g":     "invalid data character",
+		"lt1igcx5c0":    "invalid data character",
+		"in1muywd":      "checksum too short",
+		"mm1crxm3i":     "invalid character in checksum",
+		"au1s5cgom":     "invalid character in checksum",
+		"M1VUXWEZ":      "checksum computed with an uppercase prefix",
+		"16plkw9":       "empty prefix",
+		"1p2gdwpf":      "empty prefix",
+	}
+	for s, why := range invalid {
+		if _, _, err := bech32mDecode(s); err == nil {
+			t.Errorf("%q accepted (%s)", s, why)
+		}
+	}
+}
+
+func TestAddressRoundTrip(t *testing.T) {
+	setTestNetwork(t, "mainnet")
+	hexAddr := strings.Repeat("ab", addressLen)
+
+	encoded, err := FormatAddress(hexAddr)
+	if err != nil {
+		t.Fatalf("FormatAddress: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "gc1q") {
+		t.Errorf("mainnet address %q should start with gc1q", encoded)
+	}
+	for _, s := range []string{encoded, strings.ToUpper(encoded)} {
+		if got, err := ParseAddress(s); err != nil || got != hexAddr {
+			t.Errorf("ParseAddress(%q): got %q, %v", s, got, err)
+		}
+	}
+
+	if _, err := FormatAddress("abcd"); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("FormatAddress of a short address: got %v", err)
+	}
+	if _, err := ParseAddress(hexAddr); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("raw hex address accepted: %v", err)
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	setTestNetwork(t, "testnet")
+	testnet, _ := FormatAddress(strings.Repeat("01", addressLen))
+	setTestNetwork(t, "mainnet")
+	mainnet, _ := FormatAddress(strings.Repeat("01", addressLen))
+
+	// Change one data character, keeping it in the charset
+	i := len(mainnet) - 10
+	c := byte('q')
+	if mainnet[i] == 'q' {
+		c = 'p'
+	}
+	typo := mainnet[:i] + string(c) + mainnet[i+1:]
+
+	tests := map[string]string{
+		"wrong network":   testnet,
+		"unknown prefix":  bech32mEncode("xx", append([]byte{AddressVersion}, convertBits(make([]byte, addressLen), 8, 5, true)...)),
+		"bad checksum":    typo,
+		"mixed case":      strings.ToUpper(mainnet[:5]) + mainnet[5:],
+		"wrong version":   bech32mEncode("gc", append([]byte{1}, convertBits(make([]byte, addressLen), 8, 5, true)...)),
+		"wrong length":    bech32mEncode("gc", append([]byte{AddressVersion}, convertBits(make([]byte, 20), 8, 5, true)...)),
+		"nonzero padding": bech32mEncode("gc", append([]byte{AddressVersion}, append(convertBits(make([]byte, addressLen-1), 8, 5, true), 1, 1)...)),
+	}
+	for name, s := range tests {
+		if _, err := ParseAddress(s); !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("%s: %q got %v", name, s, err)
+		}
+	}
+	if _, err := ParseAddress(testnet); err == nil || !strings.Contains(err.Error(), "testnet") {
+		t.Errorf("testnet address on mainnet should name its network: %v", err)
+	}
+}
+
+// setTestNetwork switches the address network for the rest of the test
+func setTestNetwork(t *testing.T, name string) {
+	t.Setenv(NetworkEnv, name)
+}
@@ -0,0 +1,176 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ContactsFile is the address book file in the wallets directory
+const ContactsFile = "contacts.json"
+
+// contactsVersion is the version of the address book format
+const contactsVersion = 1
+
+// Contact is an address book entry: a name for an account whose keys aren't local
+type Contact struct {
+	Name string `json:"name"`
+	// Address is the account address in its hex form, see ParseAddress
+	Address string `json:"address"`
+}
+
+// contactsFile is the JSON layout of the address book. Addresses are stored
+// encoded, so the file is readable and tied to its network.
+type contactsFile struct {
+	Version  int               `json:"version"`
+	Contacts map[string]string `json:"contacts"`
+}
+
+func contactsPath() string {
+	return filepath.Join(Dir(), ContactsFile)
+}
+
+// readContacts returns the address book by name, with decoded addresses. A missing
+// file is an empty address book.
+func readContacts() (map[string]string, error) {
+	data, err := os.ReadFile(contactsPath())
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f contactsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid address book: %w", err)
+	}
+	if f.Version != contactsVersion {
+		return nil, fmt.Errorf("unsupported address book version %d", f.Version)
+	}
+	contacts := make(map[string]string, len(f.Contacts))
+	for name, encoded := range f.Contacts {
+		address, err := ParseAddress(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("contact %s: %w", name, err)
+		}
+		contacts[name] = address
+	}
+	return contacts, nil
+}
+
+// writeContacts saves the address book through a temporary file, like wallet files
+func writeContacts(contacts map[string]string) error {
+	f := contactsFile{Version: contactsVersion, Contacts: make(map[string]string, len(contacts))}
+	for name, address := range contacts {
+		encoded, err := FormatAddress(address)
+		if err != nil {
+			return err
+		}
+		f.Contacts[name] = encoded
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(Dir(), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(Dir(), ".contacts-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	defer invalidateAddressIndex()
+	return os.Rename(tmp.Name(), contactsPath())
+}
+
+// Contacts returns the address book, sorted by name
+func Contacts() ([]Contact, error) {
+	contacts, err := readContacts()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Contact, 0, len(contacts))
+	for name, address := range contacts {
+		out = append(out, Contact{Name: name, Address: address})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// AddContact saves address, in hex form, under name in the address book. A contact
+// with that name is replaced; a local wallet with that name is an error, as wallet
+// names take precedence.
+func AddContact(name, address string) error {
+	if err := CheckName(name); err != nil {
+		return err
+	}
+	if WalletExists(name) {
+		return fmt.Errorf("a wallet named %s already exists", name)
+	}
+	if _, err := FormatAddress(address); err != nil {
+		return err
+	}
+	contacts, err := readContacts()
+	if err != nil {
+		return err
+	}
+	contacts[name] = address
+	return writeContacts(contacts)
+}
+
+// RemoveContact deletes a contact from the address book
+func RemoveContact(name string) error {
+	contacts, err := readContacts()
+	if err != nil {
+		return err
+	}
+	if _, ok := contacts[name]; !ok {
+		return fmt.Errorf("no contact named %s", name)
+	}
+	delete(contacts, name)
+	return writeContacts(contacts)
+}
+
+// LookupContact returns the address of a contact
+func LookupContact(name string) (string, bool) {
+	contacts, err := readContacts()
+	if err != nil {
+		return "", false
+	}
+	address, ok := contacts[name]
+	return address, ok
+}
+
+// ResolveAccount returns the account address, in hex form, that s names: a local
+// wallet, then a contact, then an encoded address
+func ResolveAccount(s string) (string, error) {
+	if WalletExists(s) {
+		pub, err := LoadPublicKey(s)
+		if err != nil {
+			return "", fmt.Errorf("wallet %s: %w", s, err)
+		}
+		return pub.Address(), nil
+	}
+	if address, ok := LookupContact(s); ok {
+		return address, nil
+	}
+	address, err := ParseAddress(s)
+	if err != nil {
+		if errors.Is(err, ErrInvalidAddress) {
+			return "", fmt.Errorf("%s is neither a wallet, a contact nor a valid address: %w", s, err)
+		}
+		return "", err
+	}
+	return address, nil
+}
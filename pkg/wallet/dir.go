@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DirEnv is the environment variable holding the directory wallet files are kept in
+const DirEnv = "WALLETS_DIR"
+
+// DefaultDir is the directory wallet files are kept in unless DirEnv or SetDir
+// chooses another one
+const DefaultDir = "wallets"
+
+// walletSuffix ends the file name of every wallet: "<name>_wallet.json"
+const walletSuffix = "_wallet.json"
+
+// dir is the directory set by SetDir, overriding DirEnv
+var dir string
+
+// Dir returns the directory wallet files are kept in
+func Dir() string {
+	if dir != "" {
+		return dir
+	}
+	if d := os.Getenv(DirEnv); d != "" {
+		return d
+	}
+	return DefaultDir
+}
+
+// SetDir makes the package keep wallet files in d, e.g. from a command line flag
+func SetDir(d string) {
+	dir = d
+	invalidateAddressIndex()
+}
+
+// walletPath returns the file a wallet is stored in
+func walletPath(name string) string {
+	return filepath.Join(Dir(), name+walletSuffix)
+}
+
+// FilePath returns the file the wallet name is stored in, whether it exists or not
+func FilePath(name string) string {
+	return walletPath(name)
+}
+
+// CheckName rejects wallet names that can't be used as part of a file name
+func CheckName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return errors.New("invalid wallet name " + `"` + name + `"`)
+	}
+	return nil
+}
+
+// addressIndex maps addresses to the names of the local wallets controlling them
+// and of contacts, so resolving names doesn't read every wallet file each time.
+// It's built on first use and rebuilt when the wallets directory changes.
+var addressIndex struct {
+	sync.Mutex
+	dir     string
+	modTime time.Time
+	names   map[string]string
+}
+
+// invalidateAddressIndex makes the next lookup rebuild the index, after this
+// process changed the wallet files
+func invalidateAddressIndex() {
+	addressIndex.Lock()
+	addressIndex.names = nil
+	addressIndex.Unlock()
+}
+
+// lookupAddressName returns the name of the local wallet controlling address
+func lookupAddressName(address string) (string, bool) {
+	d := Dir()
+	info, err := os.Stat(d)
+	if err != nil {
+		return "", false
+	}
+
+	addressIndex.Lock()
+	defer addressIndex.Unlock()
+	// Another process adding, renaming or deleting a wallet changes the directory's modification time
+	if addressIndex.names == nil || addressIndex.dir != d || !addressIndex.modTime.Equal(info.ModTime()) {
+		addressIndex.names = readAddressIndex(d)
+		addressIndex.dir = d
+		addressIndex.modTime = info.ModTime()
+	}
+	name, ok := addressIndex.names[address]
+	return name, ok
+}
+
+// readAddressIndex reads the public keys of all wallets in d, then the address book.
+// When several wallets or contacts have the same address, the first one by name
+// is kept, and wallets take precedence over contacts.
+func readAddressIndex(d string) map[string]string {
+	names := make(map[string]string)
+	files, _ := os.ReadDir(d)
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), walletSuffix) {
+			continue
+		}
+		wf, err := readWalletFile(filepath.Join(d, f.Name()))
+		if err != nil {
+			continue
+		}
+		pub, err := wf.publicKey()
+		if err != nil {
+			continue
+		}
+		address := PublicKeyToAddress(pub)
+		if _, ok := names[address]; !ok {
+			names[address] = strings.TrimSuffix(f.Name(), walletSuffix)
+		}
+	}
+
+	contacts, _ := Contacts()
+	for _, c := range contacts {
+		if _, ok := names[c.Address]; !ok {
+			names[c.Address] = c.Name
+		}
+	}
+	return names
+}
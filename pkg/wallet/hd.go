@@ -0,0 +1,242 @@
+package wallet
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang-chain/pkg/keys"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// HardenedOffset is added to a child index to derive a hardened child
+const HardenedOffset uint32 = 0x80000000
+
+// CoinType is the BIP-44 coin type used in derivation paths. The chain has no
+// registered SLIP-44 coin type, so it uses 1, the one shared by all testnets.
+const CoinType = 1
+
+// mnemonicEntropyBits gives 24-word mnemonics
+const mnemonicEntropyBits = 256
+
+// slip10Curve is how SLIP-10 derives keys of a scheme: the HMAC key of the master
+// key, and the group order private keys must stay below. Ed25519 keys can be
+// any 32 bytes, so it has no order and only hardened children.
+type slip10Curve struct {
+	seedKey []byte
+	order   *big.Int
+}
+
+var slip10Curves = map[keys.Scheme]slip10Curve{
+	keys.P256:      {seedKey: []byte("Nist256p1 seed"), order: elliptic.P256().Params().N},
+	keys.Secp256k1: {seedKey: []byte("Bitcoin seed"), order: secp256k1.Params().N},
+	keys.Ed25519:   {seedKey: []byte("ed25519 seed")},
+}
+
+// NewMnemonic returns a new random BIP-39 mnemonic of 24 English words
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// NormalizeMnemonic lowercases a mnemonic and collapses its whitespace,
+// so one typed by hand derives the same keys
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// ValidMnemonic reports whether mnemonic is a valid BIP-39 phrase, checksum included
+func ValidMnemonic(mnemonic string) bool {
+	return bip39.IsMnemonicValid(NormalizeMnemonic(mnemonic))
+}
+
+// AccountPath returns the BIP-44 derivation path of the account with the given index.
+// Ed25519 only has hardened children, so all of its levels are hardened.
+func AccountPath(scheme keys.Scheme, index uint32) string {
+	if scheme == keys.Ed25519 {
+		return fmt.Sprintf("m/44'/%d'/0'/0'/%d'", CoinType, index)
+	}
+	return fmt.Sprintf("m/44'/%d'/0'/0/%d", CoinType, index)
+}
+
+// DeriveAccount derives the key pair of the account with the given index from a mnemonic
+func DeriveAccount(scheme keys.Scheme, mnemonic string, index uint32) (*Wallet, error) {
+	return DeriveWallet(scheme, mnemonic, AccountPath(scheme, index))
+}
+
+// DeriveWallet derives the key pair at a BIP-32 path such as "m/44'/1'/0'/0/0" from a
+// mnemonic. Keys are derived as described in SLIP-10, which extends BIP-32 to
+// P-256 and Ed25519. For secp256k1 it gives the BIP-32 keys, except in the
+// negligible case of an invalid child key, which SLIP-10 derives again where
+// BIP-32 skips to the next index.
+func DeriveWallet(scheme keys.Scheme, mnemonic, path string) (*Wallet, error) {
+	mnemonic = NormalizeMnemonic(mnemonic)
+	if !ValidMnemonic(mnemonic) {
+		return nil, errors.New("invalid mnemonic")
+	}
+	curve, ok := slip10Curves[scheme]
+	if !ok {
+		return nil, fmt.Errorf("HD derivation isn't supported for %s", scheme)
+	}
+	indexes, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := newMasterKey(curve, scheme, bip39.NewSeed(mnemonic, ""))
+	for _, i := range indexes {
+		if key, err = key.child(i); err != nil {
+			return nil, err
+		}
+	}
+	return key.wallet()
+}
+
+// AccountName returns the wallet name an HD wallet account is saved under:
+// the wallet name itself for account 0, "<name>_<index>" for the others
+func AccountName(name string, index uint32) string {
+	if index == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s_%d", name, index)
+}
+
+// SaveHDAccounts derives the first n accounts of a mnemonic and saves each as a
+// wallet named by AccountName, encrypted with passphrase. Every file records its
+// derivation path, and the one of account 0 also keeps the mnemonic so the wallet
+// can be extended later.
+func SaveHDAccounts(name string, scheme keys.Scheme, mnemonic string, n uint32, passphrase string) ([]*Wallet, error) {
+	mnemonic = NormalizeMnemonic(mnemonic)
+	accounts := make([]*Wallet, 0, n)
+	for i := uint32(0); i < n; i++ {
+		w, err := DeriveAccount(scheme, mnemonic, i)
+		if err != nil {
+			return nil, err
+		}
+		var secret string
+		if i == 0 {
+			secret = mnemonic
+		}
+		if _, err := saveWallet(AccountName(name, i), w, AccountPath(scheme, i), secret, passphrase); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, w)
+	}
+	return accounts, nil
+}
+
+// parsePath turns a path like "m/44'/1'/0'/0/3" into child indexes
+func parsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must start with m", path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "H")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(n) >= HardenedOffset {
+			return nil, fmt.Errorf("invalid index %q in derivation path %q", part, path)
+		}
+		if hardened {
+			n += uint64(HardenedOffset)
+		}
+		indexes = append(indexes, uint32(n))
+	}
+	return indexes, nil
+}
+
+// extendedKey is a private key together with the chain code used to derive its children
+type extendedKey struct {
+	curve     slip10Curve
+	scheme    keys.Scheme
+	key       []byte
+	chainCode []byte
+}
+
+// newMasterKey derives the master key from a seed. An HMAC output that isn't
+// a valid private key is hashed again, as SLIP-10 requires.
+func newMasterKey(curve slip10Curve, scheme keys.Scheme, seed []byte) *extendedKey {
+	data := seed
+	for {
+		sum := hmacSHA512(curve.seedKey, data)
+		if curve.validScalar(sum[:32]) {
+			return &extendedKey{curve: curve, scheme: scheme, key: sum[:32], chainCode: sum[32:]}
+		}
+		data = sum
+	}
+}
+
+// child derives the child key with index i. Indexes from HardenedOffset up are hardened.
+func (k *extendedKey) child(i uint32) (*extendedKey, error) {
+	var data []byte
+	if i >= HardenedOffset {
+		data = append([]byte{0}, k.key...)
+	} else {
+		if k.curve.order == nil {
+			return nil, fmt.Errorf("%s keys only have hardened children", k.scheme)
+		}
+		// The raw public keys of the ECDSA schemes are SEC 1 compressed points
+		priv, err := keys.ParsePrivateKey(k.scheme, k.key)
+		if err != nil {
+			return nil, err
+		}
+		data = priv.Public().Bytes()
+	}
+	data = binary.BigEndian.AppendUint32(data, i)
+
+	for {
+		sum := hmacSHA512(k.chainCode, data)
+		if k.curve.order == nil {
+			return &extendedKey{curve: k.curve, scheme: k.scheme, key: sum[:32], chainCode: sum[32:]}, nil
+		}
+		il := new(big.Int).SetBytes(sum[:32])
+		if il.Cmp(k.curve.order) < 0 {
+			child := il.Add(il, new(big.Int).SetBytes(k.key))
+			child.Mod(child, k.curve.order)
+			if child.Sign() != 0 {
+				return &extendedKey{curve: k.curve, scheme: k.scheme, key: child.FillBytes(make([]byte, 32)), chainCode: sum[32:]}, nil
+			}
+		}
+		// SLIP-10 retries with the right half of the output instead of skipping the index
+		data = binary.BigEndian.AppendUint32(append([]byte{1}, sum[32:]...), i)
+	}
+}
+
+// wallet turns k into a key pair of its scheme
+func (k *extendedKey) wallet() (*Wallet, error) {
+	priv, err := keys.ParsePrivateKey(k.scheme, k.key)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{PrivateKey: priv, PublicKey: priv.Public()}, nil
+}
+
+// validScalar reports whether b is a valid private key: non-zero and below the group order
+func (c slip10Curve) validScalar(b []byte) bool {
+	if c.order == nil {
+		return true
+	}
+	d := new(big.Int).SetBytes(b)
+	return d.Sign() > 0 && d.Cmp(c.order) < 0
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,95 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"golang-chain/pkg/keys"
+)
+
+// slip10Step is a key of a SLIP-10 test vector, derived by the child index from the previous one
+type slip10Step struct {
+	index     uint32
+	chainCode string
+	key       string
+}
+
+// Test vector 1 of SLIP-10, for every curve it covers
+func TestSLIP10Vector1(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	const h = HardenedOffset
+
+	tests := map[keys.Scheme][]slip10Step{
+		keys.P256: {
+			{0, "beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea", "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2"},
+			{0 + h, "3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11", "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c"},
+			{1, "4187afff1aafa8445010097fb99d23aee9f599450c7bd140b6826ac22ba21d0c", "284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129"},
+			{2 + h, "98c7514f562e64e74170cc3cf304ee1ce54d6b6da4f880f313e8204c2a185318", "694596e8a54f252c960eb771a3c41e7e32496d03b954aeb90f61635b8e092aa7"},
+			{2, "ba96f776a5c3907d7fd48bde5620ee374d4acfd540378476019eab70790c63a0", "5996c37fd3dd2679039b23ed6f70b506c6b56b3cb5e424681fb0fa64caf82aaa"},
+			{1000000000, "b9b7b82d326bb9cb5b5b121066feea4eb93d5241103c9e7a18aad40f1dde8059", "21c4f269ef0a5fd1badf47eeacebeeaa3de22eb8e5b0adcd0f27dd99d34d0119"},
+		},
+		keys.Secp256k1: {
+			{0, "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+			{0 + h, "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+			{1, "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+			{2 + h, "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+			{2, "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+			{1000000000, "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+		},
+		keys.Ed25519: {
+			{0, "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+			{0 + h, "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+			{1 + h, "a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14", "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2"},
+			{2 + h, "2e69929e00b5ab250f49c3fb1c12f252de4fed2c1db88387094a0f8c4c9ccd6c", "92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9"},
+			{2 + h, "8f6d87f93d750e0efccda017d662a1b31a266e4a6f5993b15f5c1f07f74dd5cc", "30d1dc7e5fc04c31219ab25a27ae00b50f6fd66622f6e9c913253d6511d1e662"},
+			{1000000000 + h, "68789923a0cac2cd5a29172a475fe9e0fb14cd6adb5ad98a3fa70333e7afa230", "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793"},
+		},
+	}
+	for scheme, steps := range tests {
+		// The first step is the master key, its index is unused
+		key := newMasterKey(slip10Curves[scheme], scheme, seed)
+		for n, step := range steps {
+			if n > 0 {
+				var err error
+				if key, err = key.child(step.index); err != nil {
+					t.Fatalf("%s step %d: %v", scheme, n, err)
+				}
+			}
+			if got := hex.EncodeToString(key.chainCode); got != step.chainCode {
+				t.Errorf("%s step %d: chain code %s, want %s", scheme, n, got, step.chainCode)
+			}
+			if got := hex.EncodeToString(key.key); got != step.key {
+				t.Errorf("%s step %d: key %s, want %s", scheme, n, got, step.key)
+			}
+		}
+		if _, err := key.wallet(); err != nil {
+			t.Errorf("%s: derived key isn't a valid key pair: %v", scheme, err)
+		}
+	}
+}
+
+func TestEd25519NonHardenedChild(t *testing.T) {
+	key := newMasterKey(slip10Curves[keys.Ed25519], keys.Ed25519, make([]byte, 16))
+	if _, err := key.child(0); err == nil {
+		t.Errorf("non-hardened Ed25519 child derived")
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	got, err := parsePath("m/44'/1H/0'/0/3")
+	want := []uint32{44 + HardenedOffset, 1 + HardenedOffset, HardenedOffset, 0, 3}
+	if err != nil || len(got) != len(want) {
+		t.Fatalf("parsePath: got %v, %v", got, err)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("index %d: got %d, want %d", i, got[i], want[i])
+		}
+	}
+
+	for _, path := range []string{"44'/1'", "m/x", "m/2147483648", "m/-1", "m//0"} {
+		if _, err := parsePath(path); err == nil {
+			t.Errorf("%q accepted", path)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// KeystoreVersion is the version of the encrypted wallet file format.
+// Plaintext wallet files from before keystores have no version; version 1
+// stored P-256 keys as PEM, version 2 stores raw keys of any scheme as hex.
+const KeystoreVersion = 2
+
+// scrypt parameters for new keystores: 128 MiB of memory and about half a second per attempt
+const (
+	scryptN      = 1 << 17
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	scryptMaxN   = 1 << 20
+	// scryptMaxMemory bounds the 128·N·r bytes scrypt needs for a keystore read from a file
+	scryptMaxMemory = 256 << 20
+)
+
+var (
+	// ErrWrongPassphrase is returned when a keystore can't be decrypted
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted wallet file")
+	// ErrAlreadyEncrypted is returned when encrypting a wallet that already is
+	ErrAlreadyEncrypted = errors.New("wallet is already encrypted")
+)
+
+// walletFile is the JSON layout of "<wallets dir>/<name>_wallet.json".
+// The scheme, public key and derivation path stay readable so wallets can be listed and
+// addresses resolved without a passphrase; the private key and mnemonic are only
+// stored in plaintext by wallets that were created before keystores.
+type walletFile struct {
+	Version    int             `json:"version,omitempty"`
+	Scheme     string          `json:"scheme,omitempty"`
+	PublicKey  string          `json:"publicKey"`
+	Path       string          `json:"path,omitempty"`
+	Crypto     *keystoreCrypto `json:"crypto,omitempty"`
+	PrivateKey string          `json:"privateKey,omitempty"`
+	Mnemonic   string          `json:"mnemonic,omitempty"`
+}
+
+// keystoreCrypto holds the encrypted secrets of a wallet and how to decrypt them
+type keystoreCrypto struct {
+	KDF        string       `json:"kdf"`
+	KDFParams  scryptParams `json:"kdfparams"`
+	Cipher     string       `json:"cipher"`
+	Nonce      string       `json:"nonce"`
+	Ciphertext string       `json:"ciphertext"`
+}
+
+type scryptParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"`
+}
+
+// walletSecrets is the plaintext sealed in a keystore
+type walletSecrets struct {
+	PrivateKey string `json:"privateKey"`
+	Mnemonic   string `json:"mnemonic,omitempty"`
+}
+
+// encrypted reports whether the file is a keystore
+func (f *walletFile) encrypted() bool {
+	return f.Crypto != nil
+}
+
+// secrets returns the private key and mnemonic of the wallet, decrypting them if needed
+func (f *walletFile) secrets(passphrase string) (*walletSecrets, error) {
+	if !f.encrypted() {
+		return &walletSecrets{PrivateKey: f.PrivateKey, Mnemonic: f.Mnemonic}, nil
+	}
+	if f.Version < 1 || f.Version > KeystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", f.Version)
+	}
+	return f.Crypto.decrypt(f.additionalData(), passphrase)
+}
+
+// additionalData is what the encryption authenticates besides the secrets:
+// the public key and, since version 2, the scheme
+func (f *walletFile) additionalData() []byte {
+	if f.Version < 2 {
+		return []byte(f.PublicKey)
+	}
+	return []byte(f.Scheme + "\n" + f.PublicKey)
+}
+
+// encrypt seals the secrets of a plaintext wallet file with passphrase
+func (f *walletFile) encrypt(passphrase string) error {
+	if f.encrypted() {
+		return ErrAlreadyEncrypted
+	}
+	f.Version = KeystoreVersion
+	c, err := encryptSecrets(&walletSecrets{PrivateKey: f.PrivateKey, Mnemonic: f.Mnemonic}, f.additionalData(), passphrase)
+	if err != nil {
+		return err
+	}
+	f.Crypto = c
+	f.PrivateKey = ""
+	f.Mnemonic = ""
+	return nil
+}
+
+// encryptSecrets derives a key from passphrase with scrypt and seals the secrets
+// with AES-256-GCM. The public key and scheme are authenticated along with them, so they can't
+// be swapped in the file without decryption failing.
+func encryptSecrets(secrets *walletSecrets, additionalData []byte, passphrase string) (*keystoreCrypto, error) {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	params := scryptParams{N: scryptN, R: scryptR, P: scryptP}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	params.Salt = hex.EncodeToString(salt)
+
+	aead, err := params.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &keystoreCrypto{
+		KDF:        "scrypt",
+		KDFParams:  params,
+		Cipher:     "aes-256-gcm",
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, plaintext, additionalData)),
+	}, nil
+}
+
+// decrypt opens the sealed secrets with passphrase
+func (c *keystoreCrypto) decrypt(additionalData []byte, passphrase string) (*walletSecrets, error) {
+	if c.KDF != "scrypt" || c.Cipher != "aes-256-gcm" {
+		return nil, fmt.Errorf("unsupported keystore kdf %q or cipher %q", c.KDF, c.Cipher)
+	}
+	aead, err := c.KDFParams.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(c.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid keystore nonce")
+	}
+	ciphertext, err := hex.DecodeString(c.Ciphertext)
+	if err != nil {
+		return nil, errors.New("invalid keystore ciphertext")
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	var secrets walletSecrets
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, err
+	}
+	return &secrets, nil
+}
+
+// aead derives the AES-GCM cipher for passphrase. Parameters come from the file, so
+// they're bounded to keep a crafted file from using more than scryptMaxMemory, twice
+// what new keystores use, or from running for minutes.
+func (p scryptParams) aead(passphrase string) (cipher.AEAD, error) {
+	if p.N < 2 || p.N > scryptMaxN || p.N&(p.N-1) != 0 || p.R < 1 || p.R > 32 || p.P < 1 || p.P > 16 ||
+		128*p.N*p.R > scryptMaxMemory {
+		return nil, fmt.Errorf("unsupported scrypt parameters n=%d r=%d p=%d", p.N, p.R, p.P)
+	}
+	salt, err := hex.DecodeString(p.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, errors.New("invalid keystore salt")
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, p.N, p.R, p.P, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readWalletFile reads and parses the file of a wallet
+func readWalletFile(path string) (*walletFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f walletFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// writeWalletFile writes f to path through a temporary file, so an interrupted
+// write never leaves a truncated wallet behind
+func writeWalletFile(path string, f *walletFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".wallet-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	defer invalidateAddressIndex()
+	return os.Rename(tmp.Name(), path)
+}
+
+// IsEncrypted reports whether a wallet is stored as an encrypted keystore
+func IsEncrypted(name string) (bool, error) {
+	f, err := readWalletFile(walletPath(name))
+	if err != nil {
+		return false, err
+	}
+	return f.encrypted(), nil
+}
+
+// EncryptWallet turns a plaintext wallet file into a keystore encrypted with passphrase
+func EncryptWallet(name, passphrase string) error {
+	f, err := readWalletFile(walletPath(name))
+	if err != nil {
+		return err
+	}
+	if f.encrypted() {
+		return ErrAlreadyEncrypted
+	}
+	// Decoding first makes sure the wallet is usable before its plaintext key is replaced
+	w, err := f.wallet("")
+	if err != nil {
+		return err
+	}
+	_, err = saveWallet(name, w, f.Path, f.Mnemonic, passphrase)
+	return err
+}
@@ -0,0 +1,69 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	f := &walletFile{
+		Scheme:     "p256",
+		PublicKey:  "02aabbcc",
+		PrivateKey: "1234",
+		Mnemonic:   "abandon ability able",
+	}
+	if err := f.encrypt("correct horse"); err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if !f.encrypted() || f.PrivateKey != "" || f.Mnemonic != "" || f.Version != KeystoreVersion {
+		t.Fatalf("secrets left in plaintext: %+v", f)
+	}
+	if err := f.encrypt("correct horse"); !errors.Is(err, ErrAlreadyEncrypted) {
+		t.Errorf("encrypting twice: got %v", err)
+	}
+
+	secrets, err := f.secrets("correct horse")
+	if err != nil {
+		t.Fatalf("secrets: %v", err)
+	}
+	if secrets.PrivateKey != "1234" || secrets.Mnemonic != "abandon ability able" {
+		t.Errorf("decrypted secrets: got %+v", secrets)
+	}
+
+	if _, err := f.secrets("wrong horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("wrong passphrase: got %v", err)
+	}
+
+	// The public key is authenticated, swapping it breaks decryption
+	f.PublicKey = "02ddeeff"
+	if _, err := f.secrets("correct horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("swapped public key: got %v", err)
+	}
+}
+
+func TestScryptParamsBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		p    scryptParams
+		ok   bool
+	}{
+		{"small", scryptParams{N: 1 << 10, R: 8, P: 1, Salt: "00"}, true},
+		{"no salt", scryptParams{N: 1 << 10, R: 8, P: 1}, false},
+		{"N not a power of two", scryptParams{N: 1000, R: 8, P: 1, Salt: "00"}, false},
+		{"N too large", scryptParams{N: 1 << 21, R: 1, P: 1, Salt: "00"}, false},
+		{"r zero", scryptParams{N: 1 << 10, R: 0, P: 1, Salt: "00"}, false},
+		{"p too large", scryptParams{N: 1 << 10, R: 8, P: 17, Salt: "00"}, false},
+		// 128·N·r must stay within scryptMaxMemory
+		{"4 GiB", scryptParams{N: 1 << 20, R: 32, P: 1, Salt: "00"}, false},
+		{"512 MiB", scryptParams{N: 1 << 19, R: 8, P: 1, Salt: "00"}, false},
+	}
+	for _, tc := range tests {
+		_, err := tc.p.aead("passphrase")
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: got %v", tc.name, err)
+		}
+	}
+	if 128*scryptN*scryptR > scryptMaxMemory {
+		t.Errorf("the parameters of new keystores exceed scryptMaxMemory")
+	}
+}
@@ -0,0 +1,112 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang-chain/pkg/keys"
+)
+
+// Info is the public information of a wallet, everything but its secrets
+type Info struct {
+	Name      string `json:"name"`
+	Scheme    string `json:"scheme"`
+	PublicKey string `json:"publicKey"`
+	// Address is the encoded address (see FormatAddress)
+	Address string `json:"address"`
+	// Path is the derivation path of an HD wallet account
+	Path      string `json:"path,omitempty"`
+	Encrypted bool   `json:"encrypted"`
+	// Threshold and Members describe a multisig wallet; members are "<scheme>:<hex key>"
+	Threshold int      `json:"threshold,omitempty"`
+	Members   []string `json:"members,omitempty"`
+}
+
+// WalletInfo reads the public information of a wallet, which never needs a passphrase
+func WalletInfo(name string) (*Info, error) {
+	f, err := readWalletFile(walletPath(name))
+	if err != nil {
+		return nil, err
+	}
+	pub, err := f.publicKey()
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{
+		Name:      name,
+		Scheme:    pub.Scheme().String(),
+		PublicKey: hex.EncodeToString(pub.Bytes()),
+		Address:   DisplayAddress(pub.Address()),
+		Path:      f.Path,
+		Encrypted: f.encrypted(),
+	}
+	if ms, ok := pub.(*keys.MultisigKey); ok {
+		info.Threshold = ms.Threshold()
+		for _, m := range ms.Members() {
+			info.Members = append(info.Members, fmt.Sprintf("%s:%x", m.Scheme(), m.Bytes()))
+		}
+	}
+	return info, nil
+}
+
+// ParsePEMPrivateKey decodes an unencrypted PEM private key: a P-256 key as SEC 1
+// ("EC PRIVATE KEY") or PKCS #8, or an Ed25519 key as PKCS #8 ("PRIVATE KEY")
+func ParsePEMPrivateKey(data []byte) (keys.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		priv, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return keys.FromECDSAPrivate(priv)
+	case "PRIVATE KEY":
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		switch k := priv.(type) {
+		case *ecdsa.PrivateKey:
+			return keys.FromECDSAPrivate(k)
+		case ed25519.PrivateKey:
+			return keys.ParsePrivateKey(keys.Ed25519, k.Seed())
+		}
+		return nil, fmt.Errorf("unsupported PKCS #8 key type %T", priv)
+	case "ENCRYPTED PRIVATE KEY":
+		return nil, errors.New("encrypted PEM keys aren't supported, decrypt it first")
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+// RenameWallet gives a wallet a new name. It fails if a wallet already has that name.
+func RenameWallet(name, newName string) error {
+	if err := CheckName(newName); err != nil {
+		return err
+	}
+	if !WalletExists(name) {
+		return fmt.Errorf("wallet %s does not exist", name)
+	}
+	if WalletExists(newName) {
+		return fmt.Errorf("a wallet named %s already exists", newName)
+	}
+	defer invalidateAddressIndex()
+	return os.Rename(walletPath(name), walletPath(newName))
+}
+
+// DeleteWallet removes the file of a wallet. Its key is lost unless it's backed up.
+func DeleteWallet(name string) error {
+	if !WalletExists(name) {
+		return fmt.Errorf("wallet %s does not exist", name)
+	}
+	defer invalidateAddressIndex()
+	return os.Remove(walletPath(name))
+}
@@ -0,0 +1,42 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"golang-chain/pkg/keys"
+)
+
+// ErrNoPrivateKey is returned when loading the key pair of a multisig wallet,
+// whose transactions are signed with the wallets of its members
+var ErrNoPrivateKey = errors.New("multisig wallets have no private key, sign with a member's wallet")
+
+// SaveMultisig writes the key of a multisig account to "<wallets dir>/<name>_wallet.json".
+// The file holds no secret, so it isn't encrypted. It returns the path of the file.
+func SaveMultisig(name string, k *keys.MultisigKey) (string, error) {
+	f := &walletFile{
+		Version:   KeystoreVersion,
+		Scheme:    k.Scheme().String(),
+		PublicKey: hex.EncodeToString(k.Bytes()),
+	}
+	return walletPath(name), writeWalletFile(walletPath(name), f)
+}
+
+// LoadMultisig reads the key of a multisig wallet
+func LoadMultisig(name string) (*keys.MultisigKey, error) {
+	pub, err := LoadPublicKey(name)
+	if err != nil {
+		return nil, err
+	}
+	ms, ok := pub.(*keys.MultisigKey)
+	if !ok {
+		return nil, errors.New("not a multisig wallet: " + name)
+	}
+	return ms, nil
+}
+
+// IsMultisig reports whether a wallet is a multisig account
+func IsMultisig(name string) bool {
+	_, err := LoadMultisig(name)
+	return err == nil
+}
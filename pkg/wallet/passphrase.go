@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// PassphraseEnv is the environment variable a passphrase is taken from instead of
+// prompting, for scripts and containers without a terminal
+const PassphraseEnv = "WALLET_PASSPHRASE"
+
+// PassphraseFunc returns the passphrase of an encrypted wallet when LoadWallet needs it.
+// It prompts on the terminal by default; tools can replace it.
+var PassphraseFunc = func(name string) (string, error) {
+	return ReadPassphrase(fmt.Sprintf("🔑 Passphrase for wallet %s: ", name))
+}
+
+// stdin is shared by all reads so that lines buffered by one aren't lost to the next
+var stdin = bufio.NewReader(os.Stdin)
+
+// ReadPassphrase returns the passphrase from WALLET_PASSPHRASE if it's set,
+// and otherwise prompts for it with ReadSecret
+func ReadPassphrase(prompt string) (string, error) {
+	if p := os.Getenv(PassphraseEnv); p != "" {
+		return p, nil
+	}
+	return ReadSecret(prompt)
+}
+
+// ReadSecret prints prompt and reads a line from the terminal without echoing it.
+// If stdin isn't a terminal, e.g. a pipe, the line is read as is.
+func ReadSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		p, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(p), err
+	}
+
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading from stdin: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// NewPassphrase asks for the passphrase of a new keystore, twice unless it comes
+// from WALLET_PASSPHRASE, and rejects an empty one
+func NewPassphrase() (string, error) {
+	p, err := ReadPassphrase("🔑 New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if p == "" {
+		return "", errors.New("passphrase must not be empty")
+	}
+	if os.Getenv(PassphraseEnv) != "" {
+		return p, nil
+	}
+
+	again, err := ReadPassphrase("🔑 Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if again != p {
+		return "", errors.New("passphrases don't match")
+	}
+	return p, nil
+}
@@ -0,0 +1,151 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"golang-chain/pkg/keys"
+	"golang-chain/pkg/p2p/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// signerTimeout bounds every request to a remote signer
+const signerTimeout = 10 * time.Second
+
+// remoteSigner asks a signer process for signatures by a key it holds
+type remoteSigner struct {
+	conn   *grpc.ClientConn
+	client pb.SignerServiceClient
+	name   string
+	pub    keys.PublicKey
+}
+
+// DialSigner connects to the signer process at addr, either "unix:///path/to/socket"
+// or host:port, and returns a signer for its key name. The connection isn't
+// encrypted: use a Unix socket or a private network. Signatures are verified
+// against the key before they're returned, so a faulty signer is noticed.
+func DialSigner(addr, name string) (Signer, error) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	client := pb.NewSignerServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), signerTimeout)
+	defer cancel()
+	resp, err := client.GetPublicKey(ctx, &pb.SignerKeyRequest{Key: name})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("signer %s: %w", addr, err)
+	}
+	pub, err := keys.ParsePublicKey(keys.Scheme(resp.KeyType), resp.PublicKey)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("signer %s returned an invalid key: %w", addr, err)
+	}
+	return &remoteSigner{conn: conn, client: client, name: name, pub: pub}, nil
+}
+
+func (s *remoteSigner) Public() keys.PublicKey { return s.pub }
+
+func (s *remoteSigner) Sign(hash []byte) ([]byte, error) {
+	sig, err := s.sign(hash, false)
+	if err != nil {
+		return nil, err
+	}
+	if !s.pub.Verify(hash, sig) {
+		return nil, errors.New("remote signer returned an invalid signature")
+	}
+	return sig, nil
+}
+
+// SignRecoverable asks for a signature the public key can be recovered from
+func (s *remoteSigner) SignRecoverable(hash []byte) ([]byte, error) {
+	if !keys.Recoverable(s.pub.Scheme()) {
+		return nil, errors.New(s.pub.Scheme().String() + " signatures aren't recoverable")
+	}
+	sig, err := s.sign(hash, true)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := keys.RecoverPublicKey(s.pub.Scheme(), hash, sig)
+	if err != nil || !bytes.Equal(pub.Bytes(), s.pub.Bytes()) {
+		return nil, errors.New("remote signer returned an invalid signature")
+	}
+	return sig, nil
+}
+
+func (s *remoteSigner) sign(hash []byte, recoverable bool) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), signerTimeout)
+	defer cancel()
+	resp, err := s.client.Sign(ctx, &pb.SignRequest{Key: s.name, Hash: hash, Recoverable: recoverable})
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+	return resp.Signature, nil
+}
+
+func (s *remoteSigner) Close() error { return s.conn.Close() }
+
+// SignerServer serves signatures by the keys it holds to remote signers. It runs in
+// the signer process, so the private keys never leave it.
+type SignerServer struct {
+	pb.UnimplementedSignerServiceServer
+	signers map[string]Signer
+}
+
+// NewSignerServer returns a server for the given signers, by wallet name
+func NewSignerServer(signers map[string]Signer) *SignerServer {
+	return &SignerServer{signers: signers}
+}
+
+func (s *SignerServer) signer(name string) (Signer, error) {
+	signer, ok := s.signers[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no key named %q", name)
+	}
+	return signer, nil
+}
+
+func (s *SignerServer) GetPublicKey(ctx context.Context, req *pb.SignerKeyRequest) (*pb.SignerPublicKey, error) {
+	signer, err := s.signer(req.Key)
+	if err != nil {
+		return nil, err
+	}
+	pub := signer.Public()
+	return &pb.SignerPublicKey{KeyType: uint32(pub.Scheme()), PublicKey: pub.Bytes()}, nil
+}
+
+func (s *SignerServer) Sign(ctx context.Context, req *pb.SignRequest) (*pb.SignResponse, error) {
+	signer, err := s.signer(req.Key)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Hash) != 32 {
+		return nil, status.Errorf(codes.InvalidArgument, "hash must be 32 bytes, not %d", len(req.Hash))
+	}
+
+	var sig []byte
+	if req.Recoverable {
+		rs, ok := signer.(keys.RecoverableSigner)
+		if !ok {
+			return nil, status.Errorf(codes.FailedPrecondition, "key %q can't make recoverable signatures", req.Key)
+		}
+		sig, err = rs.SignRecoverable(req.Hash)
+	} else {
+		sig, err = signer.Sign(req.Hash)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "signing with %q: %v", req.Key, err)
+	}
+	log.Printf("✍️  Signed %x with %s", req.Hash, req.Key)
+	return &pb.SignResponse{Signature: sig}, nil
+}
@@ -0,0 +1,58 @@
+package wallet
+
+import (
+	"errors"
+
+	"golang-chain/pkg/keys"
+)
+
+// SignerEnv is the environment variable holding the address of a remote signer,
+// used instead of the local wallet files when it's set
+const SignerEnv = "SIGNER_ADDR"
+
+// Signer signs hashes with the key of a wallet. The key is either loaded from the
+// local keystore or held by a separate signer process, see DialSigner, so that
+// it never enters the process asking for signatures.
+type Signer interface {
+	keys.Signer
+	// Close releases the key or the connection to the signer process
+	Close() error
+}
+
+// OpenSigner returns a signer for the wallet name: the key held by the remote signer
+// at addr, or when addr is empty the local keystore, decrypted with PassphraseFunc.
+func OpenSigner(name, addr string) (Signer, error) {
+	if addr != "" {
+		return DialSigner(addr, name)
+	}
+	w, err := LoadWallet(name)
+	if err != nil {
+		return nil, err
+	}
+	return w.Signer(), nil
+}
+
+// Signer returns a signer using the wallet's private key
+func (w *Wallet) Signer() Signer {
+	return &localSigner{key: w.PrivateKey}
+}
+
+// localSigner signs with a private key held in memory
+type localSigner struct {
+	key keys.PrivateKey
+}
+
+func (s *localSigner) Public() keys.PublicKey { return s.key.Public() }
+
+func (s *localSigner) Sign(hash []byte) ([]byte, error) { return s.key.Sign(hash) }
+
+// SignRecoverable makes a recoverable signature if the key's scheme supports it
+func (s *localSigner) SignRecoverable(hash []byte) ([]byte, error) {
+	rk, ok := s.key.(keys.RecoverablePrivateKey)
+	if !ok {
+		return nil, errors.New(s.key.Scheme().String() + " signatures aren't recoverable")
+	}
+	return rk.SignRecoverable(hash)
+}
+
+func (s *localSigner) Close() error { return nil }
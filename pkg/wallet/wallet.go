@@ -1,37 +1,45 @@
 package wallet
 
 import (
+	"bytes"
 	"crypto/ecdsa"
-	"crypto/elliptic"
-	"crypto/rand"
-	"crypto/sha256"
 	"crypto/x509"
 	"encoding/hex"
-	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"os"
-	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang-chain/pkg/keys"
 )
 
-// Wallet stores a pair of ECDSA private/public keys
+// Wallet stores a private/public key pair of one of the supported signature schemes
 type Wallet struct {
-	PrivateKey *ecdsa.PrivateKey
-	PublicKey  *ecdsa.PublicKey
+	PrivateKey keys.PrivateKey
+	PublicKey  keys.PublicKey
 }
 
-// NewWallet generates a new ECDSA key pair and returns a wallet instance
+// NewWallet generates a new key pair of the default scheme and returns a wallet instance
 func NewWallet() (*Wallet, error) {
-	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	return NewWalletWithScheme(keys.DefaultScheme)
+}
+
+// NewWalletWithScheme generates a new key pair of the given signature scheme
+func NewWalletWithScheme(scheme keys.Scheme) (*Wallet, error) {
+	priv, err := keys.GenerateKey(scheme)
 	if err != nil {
 		return nil, err
 	}
 	return &Wallet{
 		PrivateKey: priv,
-		PublicKey:  &priv.PublicKey,
+		PublicKey:  priv.Public(),
 	}, nil
 }
 
+// The PEM helpers below handle P-256 keys as stored by wallet files
+// from before keystore version 2.
+
 // EncodePublicKey converts an ECDSA public key to a PEM-encoded []byte
 func EncodePublicKey(pub *ecdsa.PublicKey) ([]byte, error) {
 	der, err := x509.MarshalPKIXPublicKey(pub)
@@ -84,81 +92,174 @@ func DecodePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
 	return x509.ParseECPrivateKey(block.Bytes)
 }
 
-// PublicKeyToAddress generates a wallet address from an ECDSA public key
-// by hashing its X and Y coordinates with SHA256
-func PublicKeyToAddress(pub *ecdsa.PublicKey) string {
-	pubBytes := append(pub.X.Bytes(), pub.Y.Bytes()...)
-	hash := sha256.Sum256(pubBytes)
-	return hex.EncodeToString(hash[:])
+// PublicKeyToAddress returns the account address controlled by a public key, in the
+// hex form used by the state and transactions (see FormatAddress for the one users see).
+// For P-256 keys it's the SHA256 of their X and Y coordinates.
+func PublicKeyToAddress(pub keys.PublicKey) string {
+	return pub.Address()
 }
 
-// LoadWallet reads a wallet from a JSON file in the "wallets/" folder
-// The file should contain base64 PEM strings for the public and private key
+// LoadWallet reads a wallet from its JSON file in the wallets directory (see Dir)
+// An encrypted wallet is decrypted with the passphrase returned by PassphraseFunc.
 func LoadWallet(name string) (*Wallet, error) {
-	path := filepath.Join("wallets", name+"_wallet.json")
-	data, err := os.ReadFile(path)
+	f, err := readWalletFile(walletPath(name))
 	if err != nil {
 		return nil, err
 	}
+	var passphrase string
+	if f.encrypted() {
+		if passphrase, err = PassphraseFunc(name); err != nil {
+			return nil, err
+		}
+	}
+	return f.wallet(passphrase)
+}
 
-	var keyData map[string]string
-	if err := json.Unmarshal(data, &keyData); err != nil {
+// LoadWalletWithPassphrase reads a wallet, decrypting it with the given passphrase
+func LoadWalletWithPassphrase(name, passphrase string) (*Wallet, error) {
+	f, err := readWalletFile(walletPath(name))
+	if err != nil {
 		return nil, err
 	}
+	return f.wallet(passphrase)
+}
 
-	privKey, err := DecodePrivateKey([]byte(keyData["privateKey"]))
+// LoadPublicKey reads only the public key of a wallet, which never needs a passphrase
+func LoadPublicKey(name string) (keys.PublicKey, error) {
+	f, err := readWalletFile(walletPath(name))
 	if err != nil {
 		return nil, err
 	}
-	pubKey, err := DecodePublicKey([]byte(keyData["publicKey"]))
+	return f.publicKey()
+}
+
+// publicKey decodes the public key of the file: raw hex of its scheme since keystore
+// version 2, a PEM-encoded P-256 key before
+func (f *walletFile) publicKey() (keys.PublicKey, error) {
+	if f.Version < 2 {
+		pub, err := DecodePublicKey([]byte(f.PublicKey))
+		if err != nil {
+			return nil, err
+		}
+		return keys.FromECDSA(pub)
+	}
+	scheme, err := keys.ParseScheme(f.Scheme)
 	if err != nil {
 		return nil, err
 	}
-
-	if privKey == nil || pubKey == nil {
-		return nil, errors.New("invalid keys")
+	raw, err := hex.DecodeString(f.PublicKey)
+	if err != nil {
+		return nil, err
 	}
-
-	return &Wallet{
-		PrivateKey: privKey,
-		PublicKey:  pubKey,
-	}, nil
+	return keys.ParsePublicKey(scheme, raw)
 }
 
-// ResolveSenderName attempts to match a given public key to a wallet name by
-// scanning through all JSON wallet files in the "wallets/" directory
-func ResolveSenderName(pub []byte) string {
-	files, err := os.ReadDir("wallets")
+// wallet decodes the key pair of the file, decrypting the private key if needed
+func (f *walletFile) wallet(passphrase string) (*Wallet, error) {
+	secrets, err := f.secrets(passphrase)
 	if err != nil {
-		return "Unknown"
+		return nil, err
+	}
+	pubKey, err := f.publicKey()
+	if err != nil {
+		return nil, err
+	}
+	if pubKey.Scheme() == keys.Multisig {
+		return nil, ErrNoPrivateKey
 	}
 
-	for _, f := range files {
-		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
-			continue
+	var privKey keys.PrivateKey
+	if f.Version < 2 {
+		priv, err := DecodePrivateKey([]byte(secrets.PrivateKey))
+		if err != nil {
+			return nil, err
 		}
-
-		data, err := os.ReadFile(filepath.Join("wallets", f.Name()))
+		privKey, err = keys.FromECDSAPrivate(priv)
 		if err != nil {
-			continue
+			return nil, err
 		}
-
-		var keyMap map[string]string
-		if err := json.Unmarshal(data, &keyMap); err != nil {
-			continue
+	} else {
+		raw, err := hex.DecodeString(secrets.PrivateKey)
+		if err != nil {
+			return nil, err
 		}
-
-		if keyMap["publicKey"] == string(pub) {
-			// Strip off "_wallet.json" to get the user-friendly wallet name
-			return f.Name()[:len(f.Name())-12]
+		if privKey, err = keys.ParsePrivateKey(pubKey.Scheme(), raw); err != nil {
+			return nil, err
 		}
 	}
 
+	if !bytes.Equal(privKey.Public().Bytes(), pubKey.Bytes()) {
+		return nil, errors.New("invalid keys")
+	}
+
+	return &Wallet{
+		PrivateKey: privKey,
+		PublicKey:  pubKey,
+	}, nil
+}
+
+// SaveWallet writes a wallet to "<wallets dir>/<name>_wallet.json" as a keystore
+// encrypted with passphrase. It returns the path of the file.
+func SaveWallet(name string, w *Wallet, passphrase string) (string, error) {
+	return saveWallet(name, w, "", "", passphrase)
+}
+
+// saveWallet writes a keystore holding w and, for HD wallets, its derivation path and mnemonic
+func saveWallet(name string, w *Wallet, path, mnemonic, passphrase string) (string, error) {
+	f := &walletFile{
+		Scheme:     w.PublicKey.Scheme().String(),
+		PublicKey:  hex.EncodeToString(w.PublicKey.Bytes()),
+		Path:       path,
+		PrivateKey: hex.EncodeToString(w.PrivateKey.Bytes()),
+		Mnemonic:   mnemonic,
+	}
+	if err := f.encrypt(passphrase); err != nil {
+		return "", err
+	}
+	return walletPath(name), writeWalletFile(walletPath(name), f)
+}
+
+// ResolveSenderName attempts to match a given public key to the name of a wallet
+// in the wallets directory
+func ResolveSenderName(pub keys.PublicKey) string {
+	if name, ok := lookupAddressName(pub.Address()); ok {
+		return name
+	}
 	return "Unknown"
 }
 
+// ResolveAddressName attempts to match an address to the name of a wallet in the
+// wallets directory. Unknown addresses are returned in their encoded form.
+func ResolveAddressName(address string) string {
+	if name, ok := lookupAddressName(address); ok {
+		return name
+	}
+	return DisplayAddress(address)
+}
+
 func WalletExists(name string) bool {
-	path := filepath.Join("wallets", name+"_wallet.json")
-	_, err := os.Stat(path)
+	_, err := os.Stat(walletPath(name))
 	return err == nil
 }
+
+// ListWallets returns the names of all wallets in the wallets directory, sorted
+func ListWallets() ([]string, error) {
+	files, err := os.ReadDir(Dir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), walletSuffix) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(f.Name(), walletSuffix))
+	}
+	// Sorting the file names would put "Alice_1" before "Alice"
+	sort.Strings(names)
+	return names, nil
+}